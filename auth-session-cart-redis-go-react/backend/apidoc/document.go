@@ -0,0 +1,138 @@
+package apidoc
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Document is the root of a generated OpenAPI document. It models only the
+// fields this API's spec needs, not the full OpenAPI 3.1 object graph.
+type Document struct {
+	OpenAPI string              `json:"openapi"`
+	Info    Info                `json:"info"`
+	Paths   map[string]PathItem `json:"paths"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem maps an HTTP method (lowercase) to the operation registered for
+// it on a given path.
+type PathItem map[string]OperationObject
+
+type OperationObject struct {
+	Summary     string                    `json:"summary,omitempty"`
+	Tags        []string                  `json:"tags,omitempty"`
+	Parameters  []ParameterObject         `json:"parameters,omitempty"`
+	RequestBody *RequestBodyObject        `json:"requestBody,omitempty"`
+	Responses   map[string]ResponseObject `json:"responses"`
+}
+
+type ParameterObject struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+type RequestBodyObject struct {
+	Required bool                       `json:"required"`
+	Content  map[string]MediaTypeObject `json:"content"`
+}
+
+type MediaTypeObject struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type ResponseObject struct {
+	Description string                     `json:"description"`
+	Content     map[string]MediaTypeObject `json:"content,omitempty"`
+}
+
+// Generate builds an OpenAPI 3.1 Document from r's registered routes.
+func (r *Registry) Generate(title, version string) *Document {
+	doc := &Document{
+		OpenAPI: "3.1.0",
+		Info:    Info{Title: title, Version: version},
+		Paths:   map[string]PathItem{},
+	}
+
+	for _, rt := range r.Routes() {
+		path := toOpenAPIPath(rt.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = PathItem{}
+		}
+		item[strings.ToLower(rt.Method)] = buildOperationObject(rt.Op)
+		doc.Paths[path] = item
+	}
+
+	return doc
+}
+
+func buildOperationObject(op Operation) OperationObject {
+	obj := OperationObject{
+		Summary:   op.Summary,
+		Tags:      op.Tags,
+		Responses: map[string]ResponseObject{},
+	}
+
+	for _, p := range op.Params {
+		obj.Parameters = append(obj.Parameters, ParameterObject{
+			Name:        p.Name,
+			In:          p.In,
+			Required:    p.Required,
+			Description: p.Description,
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+
+	if op.RequestBody != nil {
+		obj.RequestBody = &RequestBodyObject{
+			Required: true,
+			Content: map[string]MediaTypeObject{
+				"application/json": {Schema: SchemaFor(op.RequestBody)},
+			},
+		}
+	}
+
+	for _, resp := range op.Responses {
+		respObj := ResponseObject{Description: resp.Description}
+		if resp.Body != nil {
+			respObj.Content = map[string]MediaTypeObject{
+				"application/json": {Schema: SchemaFor(resp.Body)},
+			}
+		}
+		obj.Responses[strconv.Itoa(resp.StatusCode)] = respObj
+	}
+	if len(obj.Responses) == 0 {
+		obj.Responses["200"] = ResponseObject{Description: "OK"}
+	}
+
+	return obj
+}
+
+// toOpenAPIPath rewrites this API's router's "{name}"/"{name:int}" path
+// parameter syntax into OpenAPI's "{name}" template syntax.
+func toOpenAPIPath(pattern string) string {
+	trimmed := strings.Trim(pattern, "/")
+	if trimmed == "" {
+		return "/"
+	}
+
+	segs := strings.Split(trimmed, "/")
+	for i, seg := range segs {
+		if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+			continue
+		}
+		name := seg[1 : len(seg)-1]
+		if idx := strings.IndexByte(name, ':'); idx != -1 {
+			name = name[:idx]
+		}
+		segs[i] = "{" + name + "}"
+	}
+	return "/" + strings.Join(segs, "/")
+}
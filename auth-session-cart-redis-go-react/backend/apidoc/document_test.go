@@ -0,0 +1,47 @@
+package apidoc
+
+import "testing"
+
+func TestToOpenAPIPath(t *testing.T) {
+	cases := map[string]string{
+		"/users/{id:int}":         "/users/{id}",
+		"/users/search":           "/users/search",
+		"/users/{id:int}/disable": "/users/{id}/disable",
+		"/":                       "/",
+	}
+	for in, want := range cases {
+		if got := toOpenAPIPath(in); got != want {
+			t.Errorf("toOpenAPIPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGenerateProducesOperationForEveryRoute(t *testing.T) {
+	reg := NewRegistry()
+	reg.Add("GET", "/users/{id:int}", Operation{
+		Summary: "Get a user",
+		Responses: []Response{
+			{StatusCode: 200, Description: "OK", Body: sampleRequest{}},
+		},
+	})
+
+	doc := reg.Generate("test API", "0.0.1")
+	if doc.OpenAPI != "3.1.0" {
+		t.Fatalf("OpenAPI = %q, want 3.1.0", doc.OpenAPI)
+	}
+
+	item, ok := doc.Paths["/users/{id}"]
+	if !ok {
+		t.Fatalf("missing path /users/{id}, got %v", doc.Paths)
+	}
+	op, ok := item["get"]
+	if !ok {
+		t.Fatal("missing GET operation")
+	}
+	if op.Summary != "Get a user" {
+		t.Fatalf("Summary = %q", op.Summary)
+	}
+	if _, ok := op.Responses["200"]; !ok {
+		t.Fatal("missing 200 response")
+	}
+}
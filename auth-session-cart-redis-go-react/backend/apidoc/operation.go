@@ -0,0 +1,28 @@
+package apidoc
+
+// Param documents one path or query parameter.
+type Param struct {
+	Name        string
+	In          string // "path" or "query"
+	Required    bool
+	Description string
+}
+
+// Response documents one status code an operation can return. Body is a
+// zero value of the response payload's type (e.g. APIResponse{}); leave it
+// nil for bodies that aren't JSON, like the SSE stream.
+type Response struct {
+	StatusCode  int
+	Description string
+	Body        interface{}
+}
+
+// Operation documents a single route: what it does, its parameters, the
+// shape of its request body (if any), and the responses it can return.
+type Operation struct {
+	Summary     string
+	Tags        []string
+	RequestBody interface{} // a zero value of the request body's type; nil if none
+	Params      []Param
+	Responses   []Response
+}
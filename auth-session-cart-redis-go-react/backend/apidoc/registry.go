@@ -0,0 +1,50 @@
+package apidoc
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RegisteredRoute is one method+path route paired with its documentation.
+type RegisteredRoute struct {
+	Method string
+	Path   string
+	Op     Operation
+}
+
+// Registry accumulates the Operation documented for every route registered
+// through a Router or Group.
+type Registry struct {
+	routes []RegisteredRoute
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Add records op as the documentation for method+path. It panics on a
+// duplicate method+path registration rather than silently letting one
+// doc shadow the other in the generated spec.
+func (r *Registry) Add(method, path string, op Operation) {
+	for _, existing := range r.routes {
+		if existing.Method == method && existing.Path == path {
+			panic(fmt.Sprintf("apidoc: %s %s already registered", method, path))
+		}
+	}
+	r.routes = append(r.routes, RegisteredRoute{Method: method, Path: path, Op: op})
+}
+
+// Routes returns every registered route, sorted by path then method so
+// output (and test assertions) are deterministic.
+func (r *Registry) Routes() []RegisteredRoute {
+	out := make([]RegisteredRoute, len(r.routes))
+	copy(out, r.routes)
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		return out[i].Method < out[j].Method
+	})
+	return out
+}
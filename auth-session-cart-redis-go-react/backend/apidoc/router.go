@@ -0,0 +1,80 @@
+package apidoc
+
+import (
+	"net/http"
+
+	"github.com/thaolaptrinh/dev-lab/auth-session-cart-redis-go-react/backend/router"
+)
+
+// Router wraps a *router.Router so every route registration also takes an
+// Operation, making it impossible to add a route without documenting it.
+type Router struct {
+	rt       *router.Router
+	registry *Registry
+}
+
+// NewRouter returns a Router that registers handlers on rt and records
+// their documentation into registry.
+func NewRouter(rt *router.Router, registry *Registry) *Router {
+	return &Router{rt: rt, registry: registry}
+}
+
+func (dr *Router) GET(pattern string, h http.HandlerFunc, op Operation, mw ...router.Middleware) {
+	dr.registry.Add(http.MethodGet, pattern, op)
+	dr.rt.GET(pattern, h, mw...)
+}
+
+func (dr *Router) POST(pattern string, h http.HandlerFunc, op Operation, mw ...router.Middleware) {
+	dr.registry.Add(http.MethodPost, pattern, op)
+	dr.rt.POST(pattern, h, mw...)
+}
+
+func (dr *Router) PUT(pattern string, h http.HandlerFunc, op Operation, mw ...router.Middleware) {
+	dr.registry.Add(http.MethodPut, pattern, op)
+	dr.rt.PUT(pattern, h, mw...)
+}
+
+func (dr *Router) DELETE(pattern string, h http.HandlerFunc, op Operation, mw ...router.Middleware) {
+	dr.registry.Add(http.MethodDelete, pattern, op)
+	dr.rt.DELETE(pattern, h, mw...)
+}
+
+// Group returns a builder that prefixes every route registered through it
+// with prefix, mirroring router.Group but requiring an Operation per route.
+func (dr *Router) Group(prefix string) *Group {
+	return &Group{dr: dr, group: dr.rt.Group(prefix), prefix: prefix}
+}
+
+// Group is apidoc's equivalent of router.Group: it keeps the path prefix in
+// sync between the underlying router.Group and the Operation recorded in
+// the registry.
+type Group struct {
+	dr     *Router
+	group  *router.Group
+	prefix string
+}
+
+func (g *Group) Use(mw ...router.Middleware) *Group {
+	g.group.Use(mw...)
+	return g
+}
+
+func (g *Group) GET(pattern string, h http.HandlerFunc, op Operation, mw ...router.Middleware) {
+	g.dr.registry.Add(http.MethodGet, g.prefix+pattern, op)
+	g.group.GET(pattern, h, mw...)
+}
+
+func (g *Group) POST(pattern string, h http.HandlerFunc, op Operation, mw ...router.Middleware) {
+	g.dr.registry.Add(http.MethodPost, g.prefix+pattern, op)
+	g.group.POST(pattern, h, mw...)
+}
+
+func (g *Group) PUT(pattern string, h http.HandlerFunc, op Operation, mw ...router.Middleware) {
+	g.dr.registry.Add(http.MethodPut, g.prefix+pattern, op)
+	g.group.PUT(pattern, h, mw...)
+}
+
+func (g *Group) DELETE(pattern string, h http.HandlerFunc, op Operation, mw ...router.Middleware) {
+	g.dr.registry.Add(http.MethodDelete, g.prefix+pattern, op)
+	g.group.DELETE(pattern, h, mw...)
+}
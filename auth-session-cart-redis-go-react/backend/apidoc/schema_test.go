@@ -0,0 +1,47 @@
+package apidoc
+
+import "testing"
+
+type sampleRequest struct {
+	Name  string `json:"name"`
+	Age   int    `json:"age,omitempty"`
+	token string
+}
+
+func TestSchemaForStruct(t *testing.T) {
+	s := SchemaFor(sampleRequest{})
+
+	if s.Type != "object" {
+		t.Fatalf("Type = %q, want %q", s.Type, "object")
+	}
+	if _, ok := s.Properties["token"]; ok {
+		t.Fatal("unexported field token should not appear in Properties")
+	}
+	if s.Properties["name"].Type != "string" {
+		t.Fatalf("name.Type = %q, want %q", s.Properties["name"].Type, "string")
+	}
+	if s.Properties["age"].Type != "integer" {
+		t.Fatalf("age.Type = %q, want %q", s.Properties["age"].Type, "integer")
+	}
+
+	wantRequired := map[string]bool{"name": true}
+	for _, name := range s.Required {
+		if name == "age" {
+			t.Fatal("age has omitempty and should not be Required")
+		}
+		delete(wantRequired, name)
+	}
+	if len(wantRequired) != 0 {
+		t.Fatalf("missing required fields: %v", wantRequired)
+	}
+}
+
+func TestSchemaForSlice(t *testing.T) {
+	s := SchemaFor([]sampleRequest{})
+	if s.Type != "array" {
+		t.Fatalf("Type = %q, want %q", s.Type, "array")
+	}
+	if s.Items == nil || s.Items.Type != "object" {
+		t.Fatal("Items should describe the element's object schema")
+	}
+}
@@ -0,0 +1,26 @@
+package apidoc
+
+import (
+	"bytes"
+	"embed"
+	"net/http"
+)
+
+//go:embed docs
+var docsFS embed.FS
+
+// DocsHandler serves an embedded Swagger UI shell pointed at specPath. The
+// shell itself loads the swagger-ui-dist bundle from a CDN at runtime
+// rather than vendoring it, since it's a few megabytes of generated JS that
+// doesn't belong in this repo.
+func DocsHandler(specPath string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		html, err := docsFS.ReadFile("docs/index.html")
+		if err != nil {
+			http.Error(w, "docs unavailable", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(bytes.Replace(html, []byte("{{SPEC_PATH}}"), []byte(specPath), 1))
+	}
+}
@@ -0,0 +1,19 @@
+package auth
+
+import "context"
+
+type contextKey int
+
+const claimsContextKey contextKey = 0
+
+// WithClaims returns a context carrying claims, for the server's requireAuth
+// middleware to attach the caller to the request context.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey, claims)
+}
+
+// ClaimsFromContext returns the caller attached by requireAuth, if any.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey).(*Claims)
+	return claims, ok
+}
@@ -0,0 +1,19 @@
+package auth
+
+import "testing"
+
+func TestHashPasswordCheckPasswordRoundTrip(t *testing.T) {
+	hash, err := HashPassword("correct-horse-battery-staple")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	if hash == "correct-horse-battery-staple" {
+		t.Fatal("HashPassword returned the plaintext password unchanged")
+	}
+	if !CheckPassword(hash, "correct-horse-battery-staple") {
+		t.Fatal("CheckPassword rejected the password it was hashed from")
+	}
+	if CheckPassword(hash, "wrong-password") {
+		t.Fatal("CheckPassword accepted the wrong password")
+	}
+}
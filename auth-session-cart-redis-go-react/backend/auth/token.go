@@ -0,0 +1,94 @@
+// Package auth issues and verifies the JWTs used to authenticate requests,
+// and hashes the passwords stored alongside each User.
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken covers a missing/expired/mis-signed token or one of the
+// wrong type (access used as refresh or vice versa).
+var ErrInvalidToken = errors.New("auth: invalid or expired token")
+
+const (
+	accessTokenType  = "access"
+	refreshTokenType = "refresh"
+)
+
+// Claims is the JWT payload for both access and refresh tokens. Type keeps a
+// refresh token from being accepted as an access token.
+type Claims struct {
+	UserID int    `json:"uid"`
+	Role   string `json:"role"`
+	Type   string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// TokenPair is returned by /auth/login and /auth/refresh.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Issuer signs and verifies HS256 JWTs with a single shared secret.
+type Issuer struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewIssuer builds an Issuer. secret must not be empty.
+func NewIssuer(secret string, accessTTL, refreshTTL time.Duration) *Issuer {
+	return &Issuer{secret: []byte(secret), accessTTL: accessTTL, refreshTTL: refreshTTL}
+}
+
+// IssuePair mints a fresh access+refresh token pair for userID/role.
+func (iss *Issuer) IssuePair(userID int, role string) (TokenPair, error) {
+	access, err := iss.issue(userID, role, accessTokenType, iss.accessTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refresh, err := iss.issue(userID, role, refreshTokenType, iss.refreshTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return TokenPair{AccessToken: access, RefreshToken: refresh}, nil
+}
+
+func (iss *Issuer) issue(userID int, role, typ string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		UserID: userID,
+		Role:   role,
+		Type:   typ,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(iss.secret)
+}
+
+// ParseAccessToken verifies an access token and returns its claims.
+func (iss *Issuer) ParseAccessToken(token string) (*Claims, error) {
+	return iss.parse(token, accessTokenType)
+}
+
+// ParseRefreshToken verifies a refresh token and returns its claims.
+func (iss *Issuer) ParseRefreshToken(token string) (*Claims, error) {
+	return iss.parse(token, refreshTokenType)
+}
+
+func (iss *Issuer) parse(token, wantType string) (*Claims, error) {
+	claims := &Claims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return iss.secret, nil
+	})
+	if err != nil || !parsed.Valid || claims.Type != wantType {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIssuePairRoundTrip(t *testing.T) {
+	iss := NewIssuer("test-secret", time.Hour, 24*time.Hour)
+
+	pair, err := iss.IssuePair(7, "admin")
+	if err != nil {
+		t.Fatalf("IssuePair: %v", err)
+	}
+
+	access, err := iss.ParseAccessToken(pair.AccessToken)
+	if err != nil {
+		t.Fatalf("ParseAccessToken: %v", err)
+	}
+	if access.UserID != 7 || access.Role != "admin" {
+		t.Fatalf("access claims = %+v, want UserID=7 Role=%s", access, "admin")
+	}
+
+	refresh, err := iss.ParseRefreshToken(pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("ParseRefreshToken: %v", err)
+	}
+	if refresh.UserID != 7 || refresh.Role != "admin" {
+		t.Fatalf("refresh claims = %+v, want UserID=7 Role=%s", refresh, "admin")
+	}
+}
+
+func TestParseRejectsWrongTokenType(t *testing.T) {
+	iss := NewIssuer("test-secret", time.Hour, 24*time.Hour)
+	pair, err := iss.IssuePair(1, "user")
+	if err != nil {
+		t.Fatalf("IssuePair: %v", err)
+	}
+
+	if _, err := iss.ParseAccessToken(pair.RefreshToken); err != ErrInvalidToken {
+		t.Fatalf("ParseAccessToken(refresh token) err = %v, want ErrInvalidToken", err)
+	}
+	if _, err := iss.ParseRefreshToken(pair.AccessToken); err != ErrInvalidToken {
+		t.Fatalf("ParseRefreshToken(access token) err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestParseRejectsExpiredToken(t *testing.T) {
+	iss := NewIssuer("test-secret", -time.Minute, -time.Minute)
+	pair, err := iss.IssuePair(1, "user")
+	if err != nil {
+		t.Fatalf("IssuePair: %v", err)
+	}
+
+	if _, err := iss.ParseAccessToken(pair.AccessToken); err != ErrInvalidToken {
+		t.Fatalf("ParseAccessToken(expired) err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestParseRejectsWrongSecret(t *testing.T) {
+	iss := NewIssuer("test-secret", time.Hour, 24*time.Hour)
+	pair, err := iss.IssuePair(1, "user")
+	if err != nil {
+		t.Fatalf("IssuePair: %v", err)
+	}
+
+	other := NewIssuer("a-different-secret", time.Hour, 24*time.Hour)
+	if _, err := other.ParseAccessToken(pair.AccessToken); err != ErrInvalidToken {
+		t.Fatalf("ParseAccessToken with wrong secret err = %v, want ErrInvalidToken", err)
+	}
+}
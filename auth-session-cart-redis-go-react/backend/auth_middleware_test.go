@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRequireAdminRejectsNonAdmin(t *testing.T) {
+	_, handler := newTestServer(t)
+	user := loginAs(t, handler, "mai@example.com") // RoleUser, not RoleAdmin
+
+	createReq := CreateUserRequest{Name: "Someone", Email: "someone@example.com", Age: 20, Password: "hunter22"}
+	if rec := doJSON(t, handler, http.MethodPost, "/users", user, createReq, nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("POST /users as non-admin: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	if rec := doJSON(t, handler, http.MethodDelete, "/users/1", user, nil, nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("DELETE /users/1 as non-admin: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	if rec := doJSON(t, handler, http.MethodPost, "/users/1/disable", user, nil, nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("POST /users/1/disable as non-admin: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	bulkReq := BulkRequest{Operations: []BulkOperation{{Op: "delete", ID: 1}}}
+	if rec := doJSON(t, handler, http.MethodPost, "/users/bulk", user, bulkReq, nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("POST /users/bulk as non-admin: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestRequireAdminAllowsAdmin(t *testing.T) {
+	_, handler := newTestServer(t)
+	admin := loginAs(t, handler, "thao@example.com") // RoleAdmin
+
+	createReq := CreateUserRequest{Name: "Someone", Email: "someone@example.com", Age: 20, Password: "hunter22"}
+	if rec := doJSON(t, handler, http.MethodPost, "/users", admin, createReq, nil); rec.Code != http.StatusCreated {
+		t.Fatalf("POST /users as admin: status = %d, want %d, body = %s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+}
+
+func TestRequireSelfOrAdminRejectsOtherUsers(t *testing.T) {
+	_, handler := newTestServer(t)
+	mai := loginAs(t, handler, "mai@example.com") // seeded as ID 2
+
+	updateOwn := UpdateUserRequest{Name: "Mai Updated"}
+	if rec := doJSON(t, handler, http.MethodPut, "/users/2", mai, updateOwn, nil); rec.Code != http.StatusOK {
+		t.Fatalf("PUT /users/2 as self: status = %d, want %d, body = %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	updateOther := UpdateUserRequest{Name: "Thao Hijacked"}
+	if rec := doJSON(t, handler, http.MethodPut, "/users/1", mai, updateOther, nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("PUT /users/1 as a different user: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestRequireAuthRejectsMissingOrInvalidToken(t *testing.T) {
+	_, handler := newTestServer(t)
+
+	if rec := doJSON(t, handler, http.MethodPost, "/users", "", CreateUserRequest{}, nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("no token: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := doJSON(t, handler, http.MethodPost, "/users", "not-a-real-token", CreateUserRequest{}, nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("garbage token: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
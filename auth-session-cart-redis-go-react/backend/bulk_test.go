@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thaolaptrinh/dev-lab/auth-session-cart-redis-go-react/backend/storage"
+)
+
+func TestHandleBulkUsersPerItemStatus(t *testing.T) {
+	s, handler := newTestServer(t)
+	admin := loginAs(t, handler, "thao@example.com")
+
+	req := BulkRequest{Operations: []BulkOperation{
+		{Op: "create", Data: []byte(`{"name":"New One","email":"new1@example.com","age":20,"password":"hunter22"}`)},
+		// Duplicates a seeded user's email, so this op should fail on its own.
+		{Op: "create", Data: []byte(`{"name":"New Two","email":"mai@example.com","age":21,"password":"hunter22"}`)},
+	}}
+
+	var resp BulkResponse
+	rec := doJSON(t, handler, http.MethodPost, "/users/bulk", admin, req, &resp)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d, body = %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if len(resp.Results) != 2 {
+		t.Fatalf("len(Results) = %d, want 2", len(resp.Results))
+	}
+	if !resp.Results[0].Success || resp.Results[0].StatusCode != http.StatusCreated {
+		t.Fatalf("Results[0] = %+v, want Success=true StatusCode=201", resp.Results[0])
+	}
+	if resp.Results[1].Success || resp.Results[1].StatusCode != http.StatusConflict {
+		t.Fatalf("Results[1] = %+v, want Success=false StatusCode=409", resp.Results[1])
+	}
+
+	if _, err := s.repo.GetByEmail(context.Background(), "new1@example.com"); err != nil {
+		t.Fatalf("the successful op should have been applied: %v", err)
+	}
+}
+
+func TestHandleBulkUsersAtomicRollsBackOnFailure(t *testing.T) {
+	s, handler := newTestServer(t)
+	admin := loginAs(t, handler, "thao@example.com")
+
+	req := BulkRequest{Atomic: true, Operations: []BulkOperation{
+		{Op: "create", Data: []byte(`{"name":"New One","email":"new1@example.com","age":20,"password":"hunter22"}`)},
+		{Op: "create", Data: []byte(`{"name":"New Two","email":"mai@example.com","age":21,"password":"hunter22"}`)},
+	}}
+
+	var resp BulkResponse
+	rec := doJSON(t, handler, http.MethodPost, "/users/bulk", admin, req, &resp)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d, body = %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if resp.Results[0].Success || resp.Results[0].StatusCode != http.StatusConflict {
+		t.Fatalf("Results[0] = %+v, want the rolled-back op reported as a 409 failure", resp.Results[0])
+	}
+	if resp.Results[1].Success || resp.Results[1].StatusCode != http.StatusConflict {
+		t.Fatalf("Results[1] = %+v, want the failing op reported as a 409", resp.Results[1])
+	}
+
+	if _, err := s.repo.GetByEmail(context.Background(), "new1@example.com"); err == nil {
+		t.Fatal("atomic batch failure should have rolled back the otherwise-successful create")
+	} else if err != storage.ErrNotFound {
+		t.Fatalf("GetByEmail after rollback: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestHandleBulkUsersIdempotencyKeyReplaysInsteadOfReapplying(t *testing.T) {
+	s, handler := newTestServer(t)
+	admin := loginAs(t, handler, "thao@example.com")
+
+	req := BulkRequest{Operations: []BulkOperation{
+		{Op: "create", Data: []byte(`{"name":"Once Only","email":"once@example.com","age":30,"password":"hunter22"}`)},
+	}}
+	body, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+
+	doBulkRequest := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/users/bulk", bytes.NewReader(body))
+		r.Header.Set("Authorization", "Bearer "+admin)
+		r.Header.Set("Idempotency-Key", "retry-once-only")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec
+	}
+
+	first := doBulkRequest()
+	if first.Code != http.StatusOK {
+		t.Fatalf("first request: status = %d, body = %s", first.Code, first.Body.String())
+	}
+
+	second := doBulkRequest()
+	if second.Code != http.StatusOK {
+		t.Fatalf("second request: status = %d, body = %s", second.Code, second.Body.String())
+	}
+	if first.Body.String() != second.Body.String() {
+		t.Fatalf("replayed response = %s, want identical to the first response %s", second.Body.String(), first.Body.String())
+	}
+
+	users, err := s.repo.Search(context.Background(), "Once Only")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(users) != 1 {
+		t.Fatalf("got %d users named %q, want exactly 1 (the retry must not double-apply)", len(users), "Once Only")
+	}
+}
@@ -0,0 +1,143 @@
+// Package events implements a small in-process publish/subscribe bus used
+// to notify SSE clients about user lifecycle changes. It has no concept of
+// HTTP; main.go's handler translates Events into wire frames.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is a single notification published on the bus.
+type Event struct {
+	ID   int64
+	Type string
+	Data interface{}
+	At   time.Time
+}
+
+// ringSize bounds how many past events Subscribe can replay for a
+// reconnecting client; older events are simply lost.
+const ringSize = 256
+
+// subscriberBuffer is how many unread events a slow subscriber can fall
+// behind by before new events are dropped for it.
+const subscriberBuffer = 32
+
+// Bus fans published events out to subscribers, filtering by type and
+// replaying recent history for clients resuming after a dropped connection.
+type Bus struct {
+	mu          sync.Mutex
+	nextID      int64
+	ring        []Event
+	subscribers map[*Subscriber]struct{}
+}
+
+// NewBus returns an empty, ready-to-use Bus.
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[*Subscriber]struct{})}
+}
+
+// Subscriber receives events from a Bus, optionally restricted to a set of
+// types. Callers read from Events() and must call Bus.Unsubscribe when done.
+type Subscriber struct {
+	events chan Event
+	types  map[string]bool // nil means "all types"
+}
+
+// Events returns the channel new events arrive on. It is closed once the
+// subscriber is unsubscribed.
+func (s *Subscriber) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscriber) accepts(eventType string) bool {
+	return s.types == nil || s.types[eventType]
+}
+
+// Subscribe registers a new subscriber restricted to types (nil or empty
+// means all types) and replays any buffered events with ID > afterID, so a
+// client reconnecting with Last-Event-ID doesn't miss anything still in the
+// ring buffer. The replay is sent after releasing mu and drops anything
+// past subscriberBuffer rather than blocking: a Subscribe call must never
+// stall with mu held, since every Publish (called synchronously from the
+// mutating HTTP handlers) would then stall behind it too.
+//
+// The backlog is fully delivered before sub is added to b.subscribers, so a
+// Publish racing with Subscribe can never be slotted into sub.events ahead
+// of the (older) backlog it's resuming from. The tradeoff is a small
+// drop window instead: an event published after the backlog snapshot but
+// before sub is registered is missed rather than reordered, which preserves
+// the monotonic Last-Event-ID contract SSE resumption depends on.
+func (b *Bus) Subscribe(types []string, afterID int64) *Subscriber {
+	sub := &Subscriber{events: make(chan Event, subscriberBuffer)}
+	if len(types) > 0 {
+		sub.types = make(map[string]bool, len(types))
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+
+	b.mu.Lock()
+	var backlog []Event
+	for _, e := range b.ring {
+		if e.ID > afterID && sub.accepts(e.Type) {
+			backlog = append(backlog, e)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, e := range backlog {
+		select {
+		case sub.events <- e:
+		default:
+		}
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+// Unsubscribe removes sub from the bus and closes its channel. It is safe
+// to call exactly once per Subscribe.
+func (b *Bus) Unsubscribe(sub *Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
+
+// Publish appends an event of the given type to the ring buffer and
+// delivers it to every interested subscriber. Subscribers whose buffer is
+// full have the event dropped rather than blocking the publisher.
+func (b *Bus) Publish(eventType string, data interface{}) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	e := Event{ID: b.nextID, Type: eventType, Data: data, At: time.Now()}
+
+	b.ring = append(b.ring, e)
+	if len(b.ring) > ringSize {
+		b.ring = b.ring[len(b.ring)-ringSize:]
+	}
+
+	for sub := range b.subscribers {
+		if !sub.accepts(e.Type) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+		}
+	}
+
+	return e
+}
@@ -0,0 +1,145 @@
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+const testTimeout = 2 * time.Second
+
+// TestSubscribeReplayPastBufferSizeDoesNotBlock reproduces a deadlock where
+// Subscribe replayed the ring buffer by sending directly into the
+// subscriber's buffered channel while still holding Bus.mu: once the
+// backlog exceeded subscriberBuffer, the blocking send never returned and
+// mu stayed locked forever.
+func TestSubscribeReplayPastBufferSizeDoesNotBlock(t *testing.T) {
+	b := NewBus()
+	for i := 0; i < subscriberBuffer*3; i++ {
+		b.Publish("user.created", i)
+	}
+
+	subDone := make(chan *Subscriber, 1)
+	go func() { subDone <- b.Subscribe(nil, 0) }()
+
+	select {
+	case sub := <-subDone:
+		b.Unsubscribe(sub)
+	case <-time.After(testTimeout):
+		t.Fatal("Subscribe blocked replaying backlog past the subscriber buffer size")
+	}
+}
+
+// TestPublishDoesNotBlockAfterReplayOverflow guards the worse half of the
+// same bug: because Subscribe held mu while blocked, every later Publish
+// call (issued synchronously from the mutating HTTP handlers) hung too.
+func TestPublishDoesNotBlockAfterReplayOverflow(t *testing.T) {
+	b := NewBus()
+	for i := 0; i < subscriberBuffer*3; i++ {
+		b.Publish("user.created", i)
+	}
+
+	subDone := make(chan *Subscriber, 1)
+	go func() { subDone <- b.Subscribe(nil, 0) }()
+
+	var sub *Subscriber
+	select {
+	case sub = <-subDone:
+	case <-time.After(testTimeout):
+		t.Fatal("Subscribe blocked replaying backlog past the subscriber buffer size")
+	}
+	defer b.Unsubscribe(sub)
+
+	publishDone := make(chan struct{}, 1)
+	go func() {
+		b.Publish("user.updated", "still alive")
+		publishDone <- struct{}{}
+	}()
+
+	select {
+	case <-publishDone:
+	case <-time.After(testTimeout):
+		t.Fatal("Publish blocked after a Subscribe call replayed a backlog larger than the buffer")
+	}
+}
+
+func TestSubscribeFiltersByType(t *testing.T) {
+	b := NewBus()
+	b.Publish("user.created", "a")
+	b.Publish("user.deleted", "b")
+
+	sub := b.Subscribe([]string{"user.deleted"}, 0)
+	defer b.Unsubscribe(sub)
+
+	select {
+	case e := <-sub.Events():
+		if e.Type != "user.deleted" {
+			t.Fatalf("got event type %q, want user.deleted", e.Type)
+		}
+	default:
+		t.Fatal("expected the matching backlog event to be replayed")
+	}
+
+	select {
+	case e := <-sub.Events():
+		t.Fatalf("unexpected second event: %+v", e)
+	default:
+	}
+}
+
+// TestSubscribeDoesNotReorderBacklogBehindARacingPublish guards the
+// monotonic Last-Event-ID contract: a Publish racing with Subscribe must
+// never be delivered ahead of the (older) backlog events Subscribe is
+// replaying, even though the backlog is sent after mu is released. The race
+// window is narrow enough that this can't force a deterministic repro of
+// the bug it guards against; it runs many iterations under -race as a
+// best-effort regression check, not a guarantee of catching a reintroduction.
+func TestSubscribeDoesNotReorderBacklogBehindARacingPublish(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		b := NewBus()
+		for j := 0; j < 5; j++ {
+			b.Publish("user.created", j)
+		}
+
+		var sub *Subscriber
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			sub = b.Subscribe(nil, 0)
+		}()
+		go func() {
+			defer wg.Done()
+			b.Publish("user.created", "racing")
+		}()
+		wg.Wait()
+
+		var gotIDs []int64
+	drain:
+		for {
+			select {
+			case e := <-sub.Events():
+				gotIDs = append(gotIDs, e.ID)
+			default:
+				break drain
+			}
+		}
+
+		for k := 1; k < len(gotIDs); k++ {
+			if gotIDs[k] < gotIDs[k-1] {
+				t.Fatalf("iteration %d: events delivered out of order: %v", i, gotIDs)
+			}
+		}
+		b.Unsubscribe(sub)
+	}
+}
+
+func TestUnsubscribeClosesChannel(t *testing.T) {
+	b := NewBus()
+	sub := b.Subscribe(nil, 0)
+	b.Unsubscribe(sub)
+
+	if _, ok := <-sub.Events(); ok {
+		t.Fatal("expected Events() to be closed after Unsubscribe")
+	}
+}
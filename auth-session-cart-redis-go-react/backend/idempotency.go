@@ -0,0 +1,76 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// idempotencyTTL is how long a cached Idempotency-Key response is replayed
+// for before a retry is treated as a new request.
+const idempotencyTTL = 10 * time.Minute
+
+// idempotencySweepInterval is how often the background sweep removes
+// expired entries. Most callers mint a fresh Idempotency-Key per request
+// and never retry it, so relying on read-triggered eviction of only the
+// matching key would let the map grow without bound; the sweep bounds it
+// to roughly one idempotencyTTL window of keys regardless of retries.
+const idempotencySweepInterval = time.Minute
+
+type idempotencyEntry struct {
+	expiresAt time.Time
+	status    int
+	body      []byte
+}
+
+// idempotencyCache stores the response of Idempotency-Key-bearing requests
+// so a client retry gets the original result instead of re-applying it.
+// Entries are also lazily expired on a matching read, but the background
+// sweep is what actually bounds memory use.
+type idempotencyCache struct {
+	mu      sync.Mutex
+	entries map[string]idempotencyEntry
+}
+
+func newIdempotencyCache() *idempotencyCache {
+	c := &idempotencyCache{entries: make(map[string]idempotencyEntry)}
+	go c.sweepLoop()
+	return c
+}
+
+// sweepLoop periodically removes expired entries, for keys that are never
+// retried and so would otherwise never be evicted by get.
+func (c *idempotencyCache) sweepLoop() {
+	ticker := time.NewTicker(idempotencySweepInterval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		c.sweep(now)
+	}
+}
+
+func (c *idempotencyCache) sweep(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *idempotencyCache) get(key string) (body []byte, status int, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, found := c.entries[key]
+	if !found || time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, 0, false
+	}
+	return entry.body, entry.status, true
+}
+
+func (c *idempotencyCache) put(key string, status int, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = idempotencyEntry{expiresAt: time.Now().Add(idempotencyTTL), status: status, body: body}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdempotencyCachePutGetRoundTrip(t *testing.T) {
+	c := newIdempotencyCache()
+
+	c.put("key-1", 201, []byte(`{"ok":true}`))
+
+	body, status, ok := c.get("key-1")
+	if !ok {
+		t.Fatal("expected a cached entry for key-1")
+	}
+	if status != 201 || string(body) != `{"ok":true}` {
+		t.Fatalf("got status=%d body=%q, want status=201 body={\"ok\":true}", status, body)
+	}
+
+	if _, _, ok := c.get("never-put"); ok {
+		t.Fatal("expected no entry for a key that was never put")
+	}
+}
+
+func TestIdempotencyCacheGetEvictsExpiredEntry(t *testing.T) {
+	c := newIdempotencyCache()
+
+	c.mu.Lock()
+	c.entries["expired"] = idempotencyEntry{expiresAt: time.Now().Add(-time.Minute), status: 200, body: []byte("old")}
+	c.mu.Unlock()
+
+	if _, _, ok := c.get("expired"); ok {
+		t.Fatal("get should treat an expired entry as a miss")
+	}
+
+	c.mu.Lock()
+	_, stillThere := c.entries["expired"]
+	c.mu.Unlock()
+	if stillThere {
+		t.Fatal("get should have evicted the expired entry it just rejected")
+	}
+}
+
+// TestIdempotencyCacheSweepRemovesExpiredEntries guards the fix for an
+// unbounded memory leak: a client that mints a fresh Idempotency-Key per
+// request and never retries would otherwise leave its entry in the map
+// forever, since only a matching get evicted anything.
+func TestIdempotencyCacheSweepRemovesExpiredEntries(t *testing.T) {
+	c := newIdempotencyCache()
+	now := time.Now()
+
+	c.mu.Lock()
+	c.entries["expired"] = idempotencyEntry{expiresAt: now.Add(-time.Minute), status: 200, body: []byte("old")}
+	c.entries["fresh"] = idempotencyEntry{expiresAt: now.Add(time.Minute), status: 200, body: []byte("new")}
+	c.mu.Unlock()
+
+	c.sweep(now)
+
+	c.mu.Lock()
+	_, expiredStillThere := c.entries["expired"]
+	_, freshStillThere := c.entries["fresh"]
+	c.mu.Unlock()
+
+	if expiredStillThere {
+		t.Fatal("sweep should have removed the expired entry even though it was never read")
+	}
+	if !freshStillThere {
+		t.Fatal("sweep should not remove an entry that hasn't expired yet")
+	}
+}
@@ -1,27 +1,38 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/thaolaptrinh/dev-lab/auth-session-cart-redis-go-react/backend/apidoc"
+	"github.com/thaolaptrinh/dev-lab/auth-session-cart-redis-go-react/backend/auth"
+	"github.com/thaolaptrinh/dev-lab/auth-session-cart-redis-go-react/backend/events"
+	"github.com/thaolaptrinh/dev-lab/auth-session-cart-redis-go-react/backend/router"
+	"github.com/thaolaptrinh/dev-lab/auth-session-cart-redis-go-react/backend/storage"
 )
 
-type User struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Age       int       `json:"age"`
-	CreatedAt time.Time `json:"created_at"`
-}
+// User lifecycle event types published on (*server).events and streamed by
+// handleUserEvents.
+const (
+	eventUserCreated = "user.created"
+	eventUserUpdated = "user.updated"
+	eventUserDeleted = "user.deleted"
+	eventUserEdited  = "user.edited"
+)
 
 type CreateUserRequest struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Age   int    `json:"age"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Age      int    `json:"age"`
+	Password string `json:"password"`
 }
 
 type UpdateUserRequest struct {
@@ -30,334 +41,1216 @@ type UpdateUserRequest struct {
 	Age   int    `json:"age,omitempty"`
 }
 
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// BulkOperation is one entry in a POST /users/bulk request. Data is decoded
+// into CreateUserRequest or UpdateUserRequest depending on Op, mirroring
+// the single-item endpoints' request shapes.
+type BulkOperation struct {
+	Op   string          `json:"op"`
+	ID   int             `json:"id,omitempty"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+type BulkRequest struct {
+	Operations []BulkOperation `json:"operations"`
+	Atomic     bool            `json:"atomic,omitempty"`
+}
+
+// BulkItemResponse mirrors one op's outcome; Data is set on success and
+// Error on failure, matching the single-item endpoints' envelope style.
+type BulkItemResponse struct {
+	Index      int         `json:"index"`
+	StatusCode int         `json:"status_code"`
+	Success    bool        `json:"success"`
+	Data       interface{} `json:"data,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+type BulkResponse struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	Results []BulkItemResponse `json:"results"`
+}
+
 type APIResponse struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 }
 
-var users = []User{
-	{
-		ID:        1,
-		Name:      "Nguyen Van Thao",
-		Email:     "thao@example.com",
-		Age:       25,
-		CreatedAt: time.Now(),
-	},
-	{
-		ID:        2,
-		Name:      "Tran Thi Mai",
-		Email:     "mai@example.com",
-		Age:       30,
-		CreatedAt: time.Now(),
-	},
+// Pagination describes one page of a paginated list response.
+type Pagination struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+// ListUsersResponse is the envelope GET /users returns: APIResponse plus a
+// pagination block.
+type ListUsersResponse struct {
+	Success    bool           `json:"success"`
+	Message    string         `json:"message"`
+	Data       []storage.User `json:"data"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+// server holds the dependencies every handler needs. It replaces the old
+// package-level `users`/`nextID` globals.
+type server struct {
+	repo        storage.UserRepository
+	issuer      *auth.Issuer
+	events      *events.Bus
+	idempotency *idempotencyCache
+	apidoc      *apidoc.Registry
+}
+
+// authenticate validates the `Authorization: Bearer <token>` header and
+// returns the caller's claims.
+func (s *server) authenticate(r *http.Request) (*auth.Claims, error) {
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, prefix) {
+		return nil, auth.ErrInvalidToken
+	}
+	return s.issuer.ParseAccessToken(strings.TrimPrefix(header, prefix))
+}
+
+// requireAuth rejects requests without a valid access token and attaches
+// its claims to the request context for downstream handlers/middleware.
+func (s *server) requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, err := s.authenticate(r)
+		if err != nil {
+			sendJSONResponse(w, http.StatusUnauthorized, APIResponse{
+				Success: false,
+				Message: "Missing or invalid access token",
+			})
+			return
+		}
+		next(w, r.WithContext(auth.WithClaims(r.Context(), claims)))
+	}
+}
+
+// requireAdmin additionally rejects authenticated callers who aren't admins.
+func (s *server) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return s.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := auth.ClaimsFromContext(r.Context())
+		if claims.Role != storage.RoleAdmin {
+			sendJSONResponse(w, http.StatusForbidden, APIResponse{
+				Success: false,
+				Message: "Admin role required",
+			})
+			return
+		}
+		next(w, r)
+	})
 }
 
-var nextID = 3
+// requireSelfOrAdmin rejects authenticated callers who are neither an admin
+// nor the {id:int} path parameter's own account.
+func (s *server) requireSelfOrAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return s.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := auth.ClaimsFromContext(r.Context())
+		id, err := strconv.Atoi(router.Param(r, "id"))
+		if err != nil {
+			sendJSONResponse(w, http.StatusBadRequest, APIResponse{
+				Success: false,
+				Message: "Invalid user ID",
+			})
+			return
+		}
+		if claims.Role != storage.RoleAdmin && claims.UserID != id {
+			sendJSONResponse(w, http.StatusForbidden, APIResponse{
+				Success: false,
+				Message: "You can only update your own account",
+			})
+			return
+		}
+		next(w, r)
+	})
+}
 
 // Middleware để log requests
 func loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		log.Printf("%s %s - Started", r.Method, r.URL.Path)
-		
+
 		next.ServeHTTP(w, r)
-		
+
 		log.Printf("%s %s - Completed in %v", r.Method, r.URL.Path, time.Since(start))
 	})
 }
 
-// Helper function để gửi JSON response
-func sendJSONResponse(w http.ResponseWriter, statusCode int, response APIResponse) {
+// writeJSON encodes v as the JSON response body with the given status code.
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Helper function để gửi JSON response
+func sendJSONResponse(w http.ResponseWriter, statusCode int, response APIResponse) {
+	writeJSON(w, statusCode, response)
+}
+
+func (s *server) handleRoot(w http.ResponseWriter, r *http.Request) {
+	sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Welcome to Go REST API",
+		Data:    "Server is running on port 8080",
+	})
 }
 
-// Helper function để tìm user theo ID
-func findUserByID(id int) (*User, int) {
-	for i, user := range users {
-		if user.ID == id {
-			return &user, i
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+// parseListOptions reads page/per_page/sort/order/min_age/max_age/
+// email_domain off r's query string into a storage.ListOptions.
+func parseListOptions(r *http.Request) (storage.ListOptions, error) {
+	q := r.URL.Query()
+	opts := storage.ListOptions{Page: 1, PerPage: defaultPerPage, SortBy: storage.SortByName, Order: storage.OrderAsc}
+
+	if v := q.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return storage.ListOptions{}, fmt.Errorf("page must be a positive integer")
 		}
+		opts.Page = page
 	}
-	return nil, -1
-}
 
-func main() {
-	mux := http.NewServeMux()
+	if v := q.Get("per_page"); v != "" {
+		perPage, err := strconv.Atoi(v)
+		if err != nil || perPage < 1 {
+			return storage.ListOptions{}, fmt.Errorf("per_page must be a positive integer")
+		}
+		opts.PerPage = perPage
+	}
+	if opts.PerPage > maxPerPage {
+		opts.PerPage = maxPerPage
+	}
 
-	// Home endpoint
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/" {
-			sendJSONResponse(w, http.StatusNotFound, APIResponse{
-				Success: false,
-				Message: "Endpoint not found",
-			})
-			return
+	switch v := q.Get("sort"); v {
+	case "", "name":
+		opts.SortBy = storage.SortByName
+	case "age":
+		opts.SortBy = storage.SortByAge
+	case "created_at":
+		opts.SortBy = storage.SortByCreatedAt
+	default:
+		return storage.ListOptions{}, fmt.Errorf("sort must be one of name, age, created_at")
+	}
+
+	switch v := q.Get("order"); v {
+	case "", "asc":
+		opts.Order = storage.OrderAsc
+	case "desc":
+		opts.Order = storage.OrderDesc
+	default:
+		return storage.ListOptions{}, fmt.Errorf("order must be asc or desc")
+	}
+
+	if v := q.Get("min_age"); v != "" {
+		age, err := strconv.Atoi(v)
+		if err != nil {
+			return storage.ListOptions{}, fmt.Errorf("min_age must be an integer")
+		}
+		opts.MinAge = age
+	}
+	if v := q.Get("max_age"); v != "" {
+		age, err := strconv.Atoi(v)
+		if err != nil {
+			return storage.ListOptions{}, fmt.Errorf("max_age must be an integer")
 		}
+		opts.MaxAge = age
+	}
+	opts.EmailDomain = q.Get("email_domain")
+
+	return opts, nil
+}
+
+// setPaginationLinkHeader emits RFC 5988 rel="next"/"prev"/"last" Link
+// headers for the current page of a paginated listing.
+func setPaginationLinkHeader(w http.ResponseWriter, r *http.Request, page, perPage, totalPages int) {
+	if perPage <= 0 || totalPages == 0 {
+		return
+	}
+
+	linkFor := func(p int, rel string) string {
+		u := *r.URL
+		q := u.Query()
+		q.Set("page", strconv.Itoa(p))
+		q.Set("per_page", strconv.Itoa(perPage))
+		u.RawQuery = q.Encode()
+		return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+	}
 
-		sendJSONResponse(w, http.StatusOK, APIResponse{
-			Success: true,
-			Message: "Welcome to Go REST API",
-			Data:    "Server is running on port 8080",
+	var links []string
+	if page < totalPages {
+		links = append(links, linkFor(page+1, "next"))
+	}
+	if page > 1 {
+		links = append(links, linkFor(page-1, "prev"))
+	}
+	links = append(links, linkFor(totalPages, "last"))
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+// GET /users
+func (s *server) handleListUsers(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseListOptions(r)
+	if err != nil {
+		sendJSONResponse(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: err.Error(),
 		})
+		return
+	}
+
+	result, err := s.repo.List(r.Context(), opts)
+	if err != nil {
+		sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to list users",
+		})
+		return
+	}
+
+	totalPages := 0
+	if opts.PerPage > 0 {
+		totalPages = (result.Total + opts.PerPage - 1) / opts.PerPage
+	}
+	setPaginationLinkHeader(w, r, opts.Page, opts.PerPage, totalPages)
+
+	writeJSON(w, http.StatusOK, ListUsersResponse{
+		Success: true,
+		Message: "Users retrieved successfully",
+		Data:    result.Users,
+		Pagination: Pagination{
+			Page:       opts.Page,
+			PerPage:    opts.PerPage,
+			Total:      result.Total,
+			TotalPages: totalPages,
+		},
 	})
+}
 
-	// GET /users - Lấy danh sách tất cả users
-	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			sendJSONResponse(w, http.StatusOK, APIResponse{
-				Success: true,
-				Message: "Users retrieved successfully",
-				Data:    users,
-			})
+// POST /users (admin only)
+func (s *server) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	var req CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendJSONResponse(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid JSON format",
+		})
+		return
+	}
 
-		case http.MethodPost:
-			// POST /users - Tạo user mới
-			var req CreateUserRequest
-			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-				sendJSONResponse(w, http.StatusBadRequest, APIResponse{
-					Success: false,
-					Message: "Invalid JSON format",
-				})
-				return
-			}
+	// Validation
+	if req.Name == "" || req.Email == "" || req.Password == "" {
+		sendJSONResponse(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Name, email and password are required",
+		})
+		return
+	}
 
-			// Validation
-			if req.Name == "" || req.Email == "" {
-				sendJSONResponse(w, http.StatusBadRequest, APIResponse{
-					Success: false,
-					Message: "Name and email are required",
-				})
-				return
-			}
+	passwordHash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to create user",
+		})
+		return
+	}
 
-			// Kiểm tra email đã tồn tại chưa
-			for _, user := range users {
-				if user.Email == req.Email {
-					sendJSONResponse(w, http.StatusConflict, APIResponse{
-						Success: false,
-						Message: "Email already exists",
-					})
-					return
-				}
-			}
+	newUser, err := s.repo.Create(r.Context(), storage.User{
+		Name:         req.Name,
+		Email:        req.Email,
+		Age:          req.Age,
+		PasswordHash: passwordHash,
+		Role:         storage.RoleUser,
+		CreatedAt:    time.Now(),
+	})
+	if errors.Is(err, storage.ErrEmailTaken) {
+		sendJSONResponse(w, http.StatusConflict, APIResponse{
+			Success: false,
+			Message: "Email already exists",
+		})
+		return
+	}
+	if err != nil {
+		sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to create user",
+		})
+		return
+	}
 
-			// Tạo user mới
-			newUser := User{
-				ID:        nextID,
-				Name:      req.Name,
-				Email:     req.Email,
-				Age:       req.Age,
-				CreatedAt: time.Now(),
-			}
-			users = append(users, newUser)
-			nextID++
+	s.events.Publish(eventUserCreated, newUser)
 
-			sendJSONResponse(w, http.StatusCreated, APIResponse{
-				Success: true,
-				Message: "User created successfully",
-				Data:    newUser,
-			})
+	sendJSONResponse(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "User created successfully",
+		Data:    newUser,
+	})
+}
 
-		default:
-			sendJSONResponse(w, http.StatusMethodNotAllowed, APIResponse{
-				Success: false,
-				Message: "Method not allowed",
-			})
-		}
+func userIDFromRequest(r *http.Request) (int, error) {
+	return strconv.Atoi(router.Param(r, "id"))
+}
+
+// GET /users/{id:int}
+func (s *server) handleGetUser(w http.ResponseWriter, r *http.Request) {
+	id, _ := userIDFromRequest(r)
+
+	user, err := s.repo.Get(r.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		sendJSONResponse(w, http.StatusNotFound, APIResponse{
+			Success: false,
+			Message: "User not found",
+		})
+		return
+	}
+	if err != nil {
+		sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to get user",
+		})
+		return
+	}
+
+	sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "User retrieved successfully",
+		Data:    user,
 	})
+}
 
-	// GET/PUT/DELETE /users/{id} - Thao tác với một user cụ thể
-	mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
-		idStr := strings.TrimPrefix(r.URL.Path, "/users/")
-		id, err := strconv.Atoi(idStr)
+// PUT /users/{id:int} (self or admin)
+func (s *server) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
+	id, _ := userIDFromRequest(r)
 
-		if err != nil {
-			sendJSONResponse(w, http.StatusBadRequest, APIResponse{
+	user, err := s.repo.Get(r.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		sendJSONResponse(w, http.StatusNotFound, APIResponse{
+			Success: false,
+			Message: "User not found",
+		})
+		return
+	}
+	if err != nil {
+		sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to get user",
+		})
+		return
+	}
+
+	var req UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendJSONResponse(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	// Cập nhật thông tin (chỉ cập nhật các field không rỗng)
+	if req.Name != "" {
+		user.Name = req.Name
+	}
+	if req.Email != "" {
+		user.Email = req.Email
+	}
+	if req.Age > 0 {
+		user.Age = req.Age
+	}
+
+	updated, err := s.repo.Update(r.Context(), user)
+	if errors.Is(err, storage.ErrEmailTaken) {
+		sendJSONResponse(w, http.StatusConflict, APIResponse{
+			Success: false,
+			Message: "Email already exists",
+		})
+		return
+	}
+	if err != nil {
+		sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to update user",
+		})
+		return
+	}
+
+	s.events.Publish(eventUserUpdated, updated)
+
+	sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "User updated successfully",
+		Data:    updated,
+	})
+}
+
+// DELETE /users/{id:int} (admin only)
+func (s *server) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	id, _ := userIDFromRequest(r)
+
+	err := s.repo.Delete(r.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		sendJSONResponse(w, http.StatusNotFound, APIResponse{
+			Success: false,
+			Message: "User not found",
+		})
+		return
+	}
+	if err != nil {
+		sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to delete user",
+		})
+		return
+	}
+
+	s.events.Publish(eventUserDeleted, map[string]int{"id": id})
+
+	sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "User deleted successfully",
+	})
+}
+
+// POST /users/{id:int}/disable (admin only)
+func (s *server) handleDisableUser(w http.ResponseWriter, r *http.Request) {
+	id, _ := userIDFromRequest(r)
+
+	if err := s.repo.DisableUser(r.Context(), id); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			sendJSONResponse(w, http.StatusNotFound, APIResponse{
 				Success: false,
-				Message: "Invalid user ID",
+				Message: "User not found",
 			})
 			return
 		}
+		sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to disable user",
+		})
+		return
+	}
 
-		switch r.Method {
-		case http.MethodGet:
-			// GET /users/{id} - Lấy thông tin một user
-			user, _ := findUserByID(id)
-			if user == nil {
-				sendJSONResponse(w, http.StatusNotFound, APIResponse{
-					Success: false,
-					Message: "User not found",
-				})
-				return
-			}
+	if user, err := s.repo.Get(r.Context(), id); err == nil {
+		s.events.Publish(eventUserEdited, user)
+	}
 
-			sendJSONResponse(w, http.StatusOK, APIResponse{
-				Success: true,
-				Message: "User retrieved successfully",
-				Data:    user,
-			})
+	sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "User disabled successfully",
+	})
+}
 
-		case http.MethodPut:
-			// PUT /users/{id} - Cập nhật thông tin user
-			user, index := findUserByID(id)
-			if user == nil {
-				sendJSONResponse(w, http.StatusNotFound, APIResponse{
-					Success: false,
-					Message: "User not found",
-				})
-				return
-			}
+// maxBulkOps caps how many operations a single POST /users/bulk call may
+// contain, so one request can't tie up a transaction indefinitely.
+const maxBulkOps = 1000
 
-			var req UpdateUserRequest
-			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-				sendJSONResponse(w, http.StatusBadRequest, APIResponse{
-					Success: false,
-					Message: "Invalid JSON format",
-				})
-				return
+// buildBulkOp validates one BulkOperation and resolves it into a
+// storage.BulkOp ready to execute. Update operations read the current user
+// first (outside any transaction) so only the fields present in Data are
+// changed, mirroring handleUpdateUser's merge behaviour.
+func (s *server) buildBulkOp(ctx context.Context, item BulkOperation) (storage.BulkOp, error) {
+	switch item.Op {
+	case "create":
+		var req CreateUserRequest
+		if len(item.Data) > 0 {
+			if err := json.Unmarshal(item.Data, &req); err != nil {
+				return storage.BulkOp{}, fmt.Errorf("invalid create data")
 			}
+		}
+		if req.Name == "" || req.Email == "" || req.Password == "" {
+			return storage.BulkOp{}, fmt.Errorf("name, email and password are required")
+		}
+		passwordHash, err := auth.HashPassword(req.Password)
+		if err != nil {
+			return storage.BulkOp{}, fmt.Errorf("failed to hash password")
+		}
+		return storage.BulkOp{
+			Kind: storage.BulkCreate,
+			User: storage.User{
+				Name:         req.Name,
+				Email:        req.Email,
+				Age:          req.Age,
+				PasswordHash: passwordHash,
+				Role:         storage.RoleUser,
+				CreatedAt:    time.Now(),
+			},
+		}, nil
 
-			// Cập nhật thông tin (chỉ cập nhật các field không rỗng)
-			if req.Name != "" {
-				users[index].Name = req.Name
-			}
-			if req.Email != "" {
-				// Kiểm tra email trùng với user khác
-				for i, u := range users {
-					if i != index && u.Email == req.Email {
-						sendJSONResponse(w, http.StatusConflict, APIResponse{
-							Success: false,
-							Message: "Email already exists",
-						})
-						return
-					}
-				}
-				users[index].Email = req.Email
-			}
-			if req.Age > 0 {
-				users[index].Age = req.Age
+	case "update":
+		if item.ID == 0 {
+			return storage.BulkOp{}, fmt.Errorf("id is required for update")
+		}
+		user, err := s.repo.Get(ctx, item.ID)
+		if err != nil {
+			return storage.BulkOp{}, fmt.Errorf("user not found")
+		}
+		var req UpdateUserRequest
+		if len(item.Data) > 0 {
+			if err := json.Unmarshal(item.Data, &req); err != nil {
+				return storage.BulkOp{}, fmt.Errorf("invalid update data")
 			}
+		}
+		if req.Name != "" {
+			user.Name = req.Name
+		}
+		if req.Email != "" {
+			user.Email = req.Email
+		}
+		if req.Age > 0 {
+			user.Age = req.Age
+		}
+		return storage.BulkOp{Kind: storage.BulkUpdate, ID: item.ID, User: user}, nil
 
-			sendJSONResponse(w, http.StatusOK, APIResponse{
-				Success: true,
-				Message: "User updated successfully",
-				Data:    users[index],
-			})
+	case "delete":
+		if item.ID == 0 {
+			return storage.BulkOp{}, fmt.Errorf("id is required for delete")
+		}
+		return storage.BulkOp{Kind: storage.BulkDelete, ID: item.ID}, nil
 
-		case http.MethodDelete:
-			// DELETE /users/{id} - Xóa user
-			_, index := findUserByID(id)
-			if index == -1 {
-				sendJSONResponse(w, http.StatusNotFound, APIResponse{
-					Success: false,
-					Message: "User not found",
-				})
-				return
-			}
+	default:
+		return storage.BulkOp{}, fmt.Errorf("unknown op %q", item.Op)
+	}
+}
 
-			// Xóa user khỏi slice
-			users = append(users[:index], users[index+1:]...)
+// statusForBulkErr maps a storage.BulkItemResult's error to the HTTP status
+// that endpoint would have returned for the equivalent single-item call.
+func statusForBulkErr(err error) int {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, storage.ErrEmailTaken):
+		return http.StatusConflict
+	case errors.Is(err, storage.ErrBatchRolledBack):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
 
-			sendJSONResponse(w, http.StatusOK, APIResponse{
-				Success: true,
-				Message: "User deleted successfully",
-			})
+func statusForBulkOp(op string) int {
+	if op == "create" {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
 
-		default:
-			sendJSONResponse(w, http.StatusMethodNotAllowed, APIResponse{
-				Success: false,
-				Message: "Method not allowed",
-			})
-		}
-	})
+// publishBulkEvent mirrors the event a successful single-item call would
+// have published for the same op.
+func (s *server) publishBulkEvent(op string, id int, user storage.User) {
+	switch op {
+	case "create":
+		s.events.Publish(eventUserCreated, user)
+	case "update":
+		s.events.Publish(eventUserUpdated, user)
+	case "delete":
+		s.events.Publish(eventUserDeleted, map[string]int{"id": id})
+	}
+}
 
-	// GET /users/search?name=xxx - Search users by name
-	mux.HandleFunc("/users/search", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			sendJSONResponse(w, http.StatusMethodNotAllowed, APIResponse{
-				Success: false,
-				Message: "Method not allowed",
-			})
+// writeBulkResponse encodes results, caching the response against
+// idempotencyKey (if present) so a retried request replays it verbatim.
+func (s *server) writeBulkResponse(w http.ResponseWriter, idempotencyKey string, status int, results []BulkItemResponse) {
+	body, err := json.Marshal(BulkResponse{Success: true, Message: "Batch processed", Results: results})
+	if err != nil {
+		sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to encode batch response",
+		})
+		return
+	}
+
+	if idempotencyKey != "" {
+		s.idempotency.put(idempotencyKey, status, body)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// POST /users/bulk (admin only) - applies a batch of create/update/delete
+// operations, reporting a per-item status instead of aborting the whole
+// batch on the first failure. atomic: true rolls the whole batch back if
+// any op fails. A matching Idempotency-Key header replays the first
+// response instead of re-applying the batch.
+func (s *server) handleBulkUsers(w http.ResponseWriter, r *http.Request) {
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if idempotencyKey != "" {
+		if body, status, ok := s.idempotency.get(idempotencyKey); ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write(body)
 			return
 		}
+	}
 
-		name := r.URL.Query().Get("name")
-		if name == "" {
-			sendJSONResponse(w, http.StatusBadRequest, APIResponse{
+	var req BulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendJSONResponse(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+	if len(req.Operations) == 0 {
+		sendJSONResponse(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "At least one operation is required",
+		})
+		return
+	}
+	if len(req.Operations) > maxBulkOps {
+		sendJSONResponse(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: fmt.Sprintf("A batch cannot contain more than %d operations", maxBulkOps),
+		})
+		return
+	}
+
+	buildErrs := make([]error, len(req.Operations))
+	ops := make([]storage.BulkOp, len(req.Operations))
+	anyBuildErr := false
+	for i, item := range req.Operations {
+		op, err := s.buildBulkOp(r.Context(), item)
+		if err != nil {
+			buildErrs[i] = err
+			anyBuildErr = true
+			continue
+		}
+		ops[i] = op
+	}
+
+	results := make([]BulkItemResponse, len(req.Operations))
+
+	// A validation failure never reaches storage. In atomic mode that means
+	// the whole batch is rejected up front, same as if storage had rolled
+	// it back after executing the other ops.
+	if anyBuildErr && req.Atomic {
+		for i := range req.Operations {
+			if buildErrs[i] != nil {
+				results[i] = BulkItemResponse{Index: i, StatusCode: http.StatusBadRequest, Success: false, Error: buildErrs[i].Error()}
+				continue
+			}
+			results[i] = BulkItemResponse{Index: i, StatusCode: http.StatusConflict, Success: false, Error: storage.ErrBatchRolledBack.Error()}
+		}
+		s.writeBulkResponse(w, idempotencyKey, http.StatusOK, results)
+		return
+	}
+
+	var validIdx []int
+	var validOps []storage.BulkOp
+	for i, err := range buildErrs {
+		if err != nil {
+			results[i] = BulkItemResponse{Index: i, StatusCode: http.StatusBadRequest, Success: false, Error: err.Error()}
+			continue
+		}
+		validIdx = append(validIdx, i)
+		validOps = append(validOps, ops[i])
+	}
+
+	if len(validOps) > 0 {
+		opResults, err := s.repo.Bulk(r.Context(), validOps, req.Atomic)
+		if err != nil {
+			sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
 				Success: false,
-				Message: "Name parameter is required",
+				Message: "Failed to process batch",
 			})
 			return
 		}
-
-		var foundUsers []User
-		for _, user := range users {
-			if strings.Contains(strings.ToLower(user.Name), strings.ToLower(name)) {
-				foundUsers = append(foundUsers, user)
+		for j, res := range opResults {
+			i := validIdx[j]
+			if res.Err != nil {
+				results[i] = BulkItemResponse{Index: i, StatusCode: statusForBulkErr(res.Err), Success: false, Error: res.Err.Error()}
+				continue
 			}
+			results[i] = BulkItemResponse{Index: i, StatusCode: statusForBulkOp(req.Operations[i].Op), Success: true, Data: res.User}
+			s.publishBulkEvent(req.Operations[i].Op, req.Operations[i].ID, res.User)
 		}
+	}
+
+	s.writeBulkResponse(w, idempotencyKey, http.StatusOK, results)
+}
 
-		sendJSONResponse(w, http.StatusOK, APIResponse{
-			Success: true,
-			Message: fmt.Sprintf("Found %d users", len(foundUsers)),
-			Data:    foundUsers,
+// GET /users/search?name=xxx
+func (s *server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		sendJSONResponse(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Name parameter is required",
 		})
+		return
+	}
+
+	foundUsers, err := s.repo.Search(r.Context(), name)
+	if err != nil {
+		sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to search users",
+		})
+		return
+	}
+
+	sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Found %d users", len(foundUsers)),
+		Data:    foundUsers,
 	})
+}
 
-	// GET /stats - Thống kê
-	mux.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			sendJSONResponse(w, http.StatusMethodNotAllowed, APIResponse{
-				Success: false,
-				Message: "Method not allowed",
-			})
+// sseHeartbeatInterval is how often handleUserEvents sends a comment line
+// to keep idle connections (and any intermediate proxies) from timing out.
+const sseHeartbeatInterval = 15 * time.Second
+
+// GET /users/events - Server-sent events stream of user lifecycle changes.
+// ?types=user.created,user.deleted restricts which event types are sent;
+// a Last-Event-ID header resumes from the bus's in-memory event backlog.
+func (s *server) handleUserEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Streaming is not supported by this server",
+		})
+		return
+	}
+
+	var types []string
+	if raw := r.URL.Query().Get("types"); raw != "" {
+		types = strings.Split(raw, ",")
+	}
+
+	var afterID int64
+	if id, err := strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64); err == nil {
+		afterID = id
+	}
+
+	sub := s.events.Subscribe(types, afterID)
+	defer s.events.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
 			return
+		case e, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			writeSSEEvent(w, e)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
 		}
+	}
+}
 
-		totalUsers := len(users)
-		var totalAge int
-		for _, user := range users {
-			totalAge += user.Age
-		}
+// sseEventPayload is the JSON body of each SSE "data:" line; the event ID
+// used for Last-Event-ID resumption travels in the SSE "id:" field instead.
+type sseEventPayload struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+	At   time.Time   `json:"at"`
+}
 
-		var averageAge float64
-		if totalUsers > 0 {
-			averageAge = float64(totalAge) / float64(totalUsers)
-		}
+func writeSSEEvent(w http.ResponseWriter, e events.Event) {
+	payload, err := json.Marshal(sseEventPayload{Type: e.Type, Data: e.Data, At: e.At})
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\n", e.ID)
+	fmt.Fprintf(w, "event: %s\n", e.Type)
+	fmt.Fprintf(w, "data: %s\n\n", payload)
+}
 
-		stats := map[string]interface{}{
-			"total_users":  totalUsers,
-			"average_age":  averageAge,
-			"server_time":  time.Now().Format("2006-01-02 15:04:05"),
-		}
+// POST /auth/login - Exchange email/password for an access+refresh token pair
+func (s *server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendJSONResponse(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	user, err := s.repo.GetByEmail(r.Context(), req.Email)
+	if err != nil || !auth.CheckPassword(user.PasswordHash, req.Password) {
+		sendJSONResponse(w, http.StatusUnauthorized, APIResponse{
+			Success: false,
+			Message: "Invalid email or password",
+		})
+		return
+	}
+	if user.IsDisabled {
+		sendJSONResponse(w, http.StatusForbidden, APIResponse{
+			Success: false,
+			Message: "This account has been disabled",
+		})
+		return
+	}
+
+	pair, err := s.issuer.IssuePair(user.ID, user.Role)
+	if err != nil {
+		sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to issue tokens",
+		})
+		return
+	}
+
+	sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Login successful",
+		Data:    pair,
+	})
+}
+
+// POST /auth/refresh - Exchange a refresh token for a fresh token pair
+func (s *server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendJSONResponse(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	claims, err := s.issuer.ParseRefreshToken(req.RefreshToken)
+	if err != nil {
+		sendJSONResponse(w, http.StatusUnauthorized, APIResponse{
+			Success: false,
+			Message: "Invalid or expired refresh token",
+		})
+		return
+	}
+
+	user, err := s.repo.Get(r.Context(), claims.UserID)
+	if err != nil || user.IsDisabled {
+		sendJSONResponse(w, http.StatusUnauthorized, APIResponse{
+			Success: false,
+			Message: "Account no longer available",
+		})
+		return
+	}
 
-		sendJSONResponse(w, http.StatusOK, APIResponse{
-			Success: true,
-			Message: "Statistics retrieved successfully",
-			Data:    stats,
+	pair, err := s.issuer.IssuePair(user.ID, user.Role)
+	if err != nil {
+		sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to issue tokens",
 		})
+		return
+	}
+
+	sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Token refreshed",
+		Data:    pair,
 	})
+}
+
+// GET /stats - Thống kê
+func (s *server) handleStats(w http.ResponseWriter, r *http.Request) {
+	result, err := s.repo.List(r.Context(), storage.ListOptions{})
+	if err != nil {
+		sendJSONResponse(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to compute statistics",
+		})
+		return
+	}
+
+	totalUsers := result.Total
+	var totalAge int
+	for _, user := range result.Users {
+		totalAge += user.Age
+	}
+
+	var averageAge float64
+	if totalUsers > 0 {
+		averageAge = float64(totalAge) / float64(totalUsers)
+	}
+
+	stats := map[string]interface{}{
+		"total_users": totalUsers,
+		"average_age": averageAge,
+		"server_time": time.Now().Format("2006-01-02 15:04:05"),
+	}
+
+	sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Statistics retrieved successfully",
+		Data:    stats,
+	})
+}
+
+// newRepository selects the storage backend from STORAGE_DRIVER /
+// DATABASE_URL (memory when unset, for local dev and tests).
+func newRepository() (storage.UserRepository, error) {
+	return storage.Open(storage.Config{
+		Driver: os.Getenv("STORAGE_DRIVER"),
+		DSN:    os.Getenv("DATABASE_URL"),
+	})
+}
+
+// newIssuer builds the JWT issuer from JWT_SECRET/JWT_ACCESS_TTL/
+// JWT_REFRESH_TTL, falling back to an insecure dev secret and 15m/7d TTLs.
+func newIssuer() *auth.Issuer {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "dev-secret-change-me"
+		log.Println("⚠️  JWT_SECRET not set, using an insecure default for local development")
+	}
+
+	return auth.NewIssuer(secret, envDuration("JWT_ACCESS_TTL", 15*time.Minute), envDuration("JWT_REFRESH_TTL", 7*24*time.Hour))
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+// apiResponseOf wraps a data shape in APIResponse for documentation
+// purposes, since every handler's response body is one of these envelopes.
+func apiResponseOf(data interface{}) APIResponse {
+	return APIResponse{Data: data}
+}
+
+// newRouter wires every endpoint onto rt, gating mutating /users routes
+// behind requireAdmin/requireSelfOrAdmin. Every route is registered through
+// dr (apidoc.Router) alongside the apidoc.Operation documenting it, so
+// GET /openapi.json and TestAllRoutesDocumented can never drift from what's
+// actually reachable.
+func (s *server) newRouter() *router.Router {
+	rt := router.New()
+	s.apidoc = apidoc.NewRegistry()
+	dr := apidoc.NewRouter(rt, s.apidoc)
+
+	dr.GET("/", s.handleRoot, apidoc.Operation{
+		Summary: "Health/welcome message",
+		Tags:    []string{"meta"},
+		Responses: []apidoc.Response{
+			{StatusCode: 200, Description: "Server is reachable", Body: apiResponseOf("")},
+		},
+	})
+	dr.GET("/stats", s.handleStats, apidoc.Operation{
+		Summary: "Aggregate user statistics",
+		Tags:    []string{"meta"},
+		Responses: []apidoc.Response{
+			{StatusCode: 200, Description: "Computed statistics", Body: apiResponseOf(map[string]interface{}{})},
+		},
+	})
+
+	dr.POST("/auth/login", s.handleLogin, apidoc.Operation{
+		Summary:     "Exchange email/password for an access+refresh token pair",
+		Tags:        []string{"auth"},
+		RequestBody: LoginRequest{},
+		Responses: []apidoc.Response{
+			{StatusCode: 200, Description: "Login successful", Body: apiResponseOf(auth.TokenPair{})},
+			{StatusCode: 401, Description: "Invalid email or password", Body: APIResponse{}},
+			{StatusCode: 403, Description: "Account disabled", Body: APIResponse{}},
+		},
+	})
+	dr.POST("/auth/refresh", s.handleRefresh, apidoc.Operation{
+		Summary:     "Exchange a refresh token for a fresh token pair",
+		Tags:        []string{"auth"},
+		RequestBody: RefreshRequest{},
+		Responses: []apidoc.Response{
+			{StatusCode: 200, Description: "Token refreshed", Body: apiResponseOf(auth.TokenPair{})},
+			{StatusCode: 401, Description: "Invalid or expired refresh token", Body: APIResponse{}},
+		},
+	})
+
+	dr.GET("/users", s.handleListUsers, apidoc.Operation{
+		Summary: "List users with pagination, filtering and sorting",
+		Tags:    []string{"users"},
+		Params: []apidoc.Param{
+			{Name: "page", In: "query", Description: "1-indexed page number, default 1"},
+			{Name: "per_page", In: "query", Description: "Page size, default 20, max 100"},
+			{Name: "sort", In: "query", Description: "name|age|created_at, default name"},
+			{Name: "order", In: "query", Description: "asc|desc, default asc"},
+			{Name: "min_age", In: "query"},
+			{Name: "max_age", In: "query"},
+			{Name: "email_domain", In: "query"},
+		},
+		Responses: []apidoc.Response{
+			{StatusCode: 200, Description: "A page of users", Body: ListUsersResponse{}},
+			{StatusCode: 400, Description: "Invalid query parameters", Body: APIResponse{}},
+		},
+	})
+	dr.GET("/users/search", s.handleSearch, apidoc.Operation{
+		Summary: "Search users by (partial, case-insensitive) name",
+		Tags:    []string{"users"},
+		Params: []apidoc.Param{
+			{Name: "name", In: "query", Required: true},
+		},
+		Responses: []apidoc.Response{
+			{StatusCode: 200, Description: "Matching users", Body: apiResponseOf([]storage.User{})},
+			{StatusCode: 400, Description: "Missing name parameter", Body: APIResponse{}},
+		},
+	})
+	dr.GET("/users/events", s.handleUserEvents, apidoc.Operation{
+		Summary: "Stream user lifecycle events over SSE",
+		Tags:    []string{"users"},
+		Params: []apidoc.Param{
+			{Name: "types", In: "query", Description: "Comma-separated event types to filter to, e.g. user.created,user.deleted"},
+		},
+		Responses: []apidoc.Response{
+			{StatusCode: 200, Description: "text/event-stream of {type, data, at} events"},
+		},
+	})
+	dr.GET("/users/{id:int}", s.handleGetUser, apidoc.Operation{
+		Summary: "Get a user by ID",
+		Tags:    []string{"users"},
+		Params: []apidoc.Param{
+			{Name: "id", In: "path", Required: true},
+		},
+		Responses: []apidoc.Response{
+			{StatusCode: 200, Description: "The requested user", Body: apiResponseOf(storage.User{})},
+			{StatusCode: 404, Description: "User not found", Body: APIResponse{}},
+		},
+	})
+
+	users := dr.Group("/users")
+	users.POST("", s.handleCreateUser, apidoc.Operation{
+		Summary:     "Create a new user",
+		Tags:        []string{"users"},
+		RequestBody: CreateUserRequest{},
+		Responses: []apidoc.Response{
+			{StatusCode: 201, Description: "User created", Body: apiResponseOf(storage.User{})},
+			{StatusCode: 400, Description: "Missing required fields", Body: APIResponse{}},
+			{StatusCode: 409, Description: "Email already exists", Body: APIResponse{}},
+		},
+	}, s.requireAdmin)
+	users.PUT("/{id:int}", s.handleUpdateUser, apidoc.Operation{
+		Summary:     "Update a user's name/email/age",
+		Tags:        []string{"users"},
+		Params:      []apidoc.Param{{Name: "id", In: "path", Required: true}},
+		RequestBody: UpdateUserRequest{},
+		Responses: []apidoc.Response{
+			{StatusCode: 200, Description: "User updated", Body: apiResponseOf(storage.User{})},
+			{StatusCode: 403, Description: "Not your account", Body: APIResponse{}},
+			{StatusCode: 404, Description: "User not found", Body: APIResponse{}},
+			{StatusCode: 409, Description: "Email already exists", Body: APIResponse{}},
+		},
+	}, s.requireSelfOrAdmin)
+	users.DELETE("/{id:int}", s.handleDeleteUser, apidoc.Operation{
+		Summary: "Delete a user",
+		Tags:    []string{"users"},
+		Params:  []apidoc.Param{{Name: "id", In: "path", Required: true}},
+		Responses: []apidoc.Response{
+			{StatusCode: 200, Description: "User deleted", Body: APIResponse{}},
+			{StatusCode: 404, Description: "User not found", Body: APIResponse{}},
+		},
+	}, s.requireAdmin)
+	users.POST("/{id:int}/disable", s.handleDisableUser, apidoc.Operation{
+		Summary: "Disable a user, rejecting future logins",
+		Tags:    []string{"users"},
+		Params:  []apidoc.Param{{Name: "id", In: "path", Required: true}},
+		Responses: []apidoc.Response{
+			{StatusCode: 200, Description: "User disabled", Body: APIResponse{}},
+			{StatusCode: 404, Description: "User not found", Body: APIResponse{}},
+		},
+	}, s.requireAdmin)
+	users.POST("/bulk", s.handleBulkUsers, apidoc.Operation{
+		Summary:     "Run a batch of create/update/delete operations",
+		Tags:        []string{"users"},
+		RequestBody: BulkRequest{},
+		Responses: []apidoc.Response{
+			{StatusCode: 200, Description: "Per-item results", Body: BulkResponse{}},
+			{StatusCode: 400, Description: "Empty or oversized batch", Body: APIResponse{}},
+		},
+	}, s.requireAdmin)
+
+	dr.GET("/openapi.json", s.handleOpenAPISpec, apidoc.Operation{
+		Summary: "OpenAPI 3.1 document for this API",
+		Tags:    []string{"meta"},
+		Responses: []apidoc.Response{
+			{StatusCode: 200, Description: "The generated OpenAPI document", Body: apidoc.Document{}},
+		},
+	})
+	dr.GET("/docs", apidoc.DocsHandler("/openapi.json"), apidoc.Operation{
+		Summary: "Swagger UI for the OpenAPI document",
+		Tags:    []string{"meta"},
+		Responses: []apidoc.Response{
+			{StatusCode: 200, Description: "HTML page"},
+		},
+	})
+
+	return rt
+}
+
+// GET /openapi.json - the OpenAPI 3.1 document generated from the routes
+// registered in newRouter.
+func (s *server) handleOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.apidoc.Generate("dev-lab user API", "1.0.0"))
+}
+
+func main() {
+	repo, err := newRepository()
+	if err != nil {
+		log.Fatal("Failed to initialize storage:", err)
+	}
+	s := &server{repo: repo, issuer: newIssuer(), events: events.NewBus(), idempotency: newIdempotencyCache()}
 
 	// Áp dụng middleware logging
-	handler := loggingMiddleware(mux)
+	handler := loggingMiddleware(s.newRouter())
 
 	fmt.Println("🚀 Server is starting on port 8080...")
 	fmt.Println("📋 Available endpoints:")
-	fmt.Println("  GET    /              - Welcome message")
-	fmt.Println("  GET    /users         - Get all users")
-	fmt.Println("  POST   /users         - Create new user")
-	fmt.Println("  GET    /users/{id}    - Get user by ID")
-	fmt.Println("  PUT    /users/{id}    - Update user by ID")
-	fmt.Println("  DELETE /users/{id}    - Delete user by ID")
-	fmt.Println("  GET    /users/search  - Search users by name")
-	fmt.Println("  GET    /stats         - Get statistics")
+	fmt.Println("  GET    /                   - Welcome message")
+	fmt.Println("  GET    /users              - Get all users")
+	fmt.Println("  POST   /users              - Create new user (admin only)")
+	fmt.Println("  GET    /users/{id}         - Get user by ID")
+	fmt.Println("  PUT    /users/{id}         - Update user by ID (self or admin)")
+	fmt.Println("  DELETE /users/{id}         - Delete user by ID (admin only)")
+	fmt.Println("  GET    /users/search       - Search users by name")
+	fmt.Println("  GET    /users/events       - Stream user lifecycle events (SSE)")
+	fmt.Println("  POST   /users/{id}/disable - Disable a user (admin only)")
+	fmt.Println("  POST   /users/bulk         - Run a batch of create/update/delete ops (admin only)")
+	fmt.Println("  POST   /auth/login         - Log in and receive a token pair")
+	fmt.Println("  POST   /auth/refresh       - Exchange a refresh token for a new pair")
+	fmt.Println("  GET    /stats              - Get statistics")
+	fmt.Println("  GET    /openapi.json       - OpenAPI 3.1 spec")
+	fmt.Println("  GET    /docs               - Swagger UI")
 	fmt.Println()
 
 	if err := http.ListenAndServe(":8080", handler); err != nil {
 		log.Fatal("Server failed to start:", err)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thaolaptrinh/dev-lab/auth-session-cart-redis-go-react/backend/auth"
+	"github.com/thaolaptrinh/dev-lab/auth-session-cart-redis-go-react/backend/events"
+	"github.com/thaolaptrinh/dev-lab/auth-session-cart-redis-go-react/backend/storage"
+)
+
+// TestAllRoutesDocumented is the "make verify-openapi" check: every route
+// newRouter wires up goes through apidoc.Router, so this mostly guards
+// against a route being documented with an empty Summary/Responses rather
+// than a route being registered without docs at all (that's a compile
+// error, since apidoc.Router.GET/POST/PUT/DELETE require an Operation).
+func TestAllRoutesDocumented(t *testing.T) {
+	s := &server{
+		repo:        storage.NewMemoryRepository(),
+		issuer:      auth.NewIssuer("test-secret", 15*time.Minute, 7*24*time.Hour),
+		events:      events.NewBus(),
+		idempotency: newIdempotencyCache(),
+	}
+	s.newRouter()
+
+	routes := s.apidoc.Routes()
+	if len(routes) == 0 {
+		t.Fatal("no routes registered in the apidoc registry")
+	}
+
+	for _, rt := range routes {
+		if rt.Op.Summary == "" {
+			t.Errorf("%s %s: missing Summary", rt.Method, rt.Path)
+		}
+		if len(rt.Op.Responses) == 0 {
+			t.Errorf("%s %s: missing Responses", rt.Method, rt.Path)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleListUsersPaginationAndLinkHeader(t *testing.T) {
+	_, handler := newTestServer(t)
+	admin := loginAs(t, handler, "thao@example.com")
+
+	// The seed already has 2 users; add 3 more so page 1 of 3 (per_page=2)
+	// has a next and a last link but no prev.
+	for i, email := range []string{"c@example.com", "d@example.com", "e@example.com"} {
+		req := CreateUserRequest{Name: "Extra", Email: email, Age: 20 + i, Password: "hunter22"}
+		if rec := doJSON(t, handler, http.MethodPost, "/users", admin, req, nil); rec.Code != http.StatusCreated {
+			t.Fatalf("seed create %s: status = %d, body = %s", email, rec.Code, rec.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/users?page=1&per_page=2", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	var resp ListUsersResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response body %q: %v", rec.Body.String(), err)
+	}
+	if resp.Pagination.Total != 5 || resp.Pagination.TotalPages != 3 {
+		t.Fatalf("Pagination = %+v, want Total=5 TotalPages=3", resp.Pagination)
+	}
+	if len(resp.Data) != 2 {
+		t.Fatalf("len(Data) = %d, want 2", len(resp.Data))
+	}
+
+	link := rec.Header().Get("Link")
+	if link == "" {
+		t.Fatal("expected a Link header on a paginated response")
+	}
+	if !containsRel(link, "next") || !containsRel(link, "last") {
+		t.Fatalf("Link header = %q, want rel=next and rel=last", link)
+	}
+	if containsRel(link, "prev") {
+		t.Fatalf("Link header = %q, page 1 should not have a prev link", link)
+	}
+
+	// Page 2 should have both a prev and a next link.
+	req = httptest.NewRequest(http.MethodGet, "/users?page=2&per_page=2", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	link = rec.Header().Get("Link")
+	if !containsRel(link, "prev") || !containsRel(link, "next") {
+		t.Fatalf("page 2 Link header = %q, want rel=prev and rel=next", link)
+	}
+}
+
+func TestHandleListUsersSortByAgeDesc(t *testing.T) {
+	_, handler := newTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/users?sort=age&order=desc", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	var resp ListUsersResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response body %q: %v", rec.Body.String(), err)
+	}
+	if len(resp.Data) < 2 {
+		t.Fatalf("expected at least the 2 seeded users, got %d", len(resp.Data))
+	}
+	if resp.Data[0].Age < resp.Data[1].Age {
+		t.Fatalf("sort=age&order=desc returned ascending order: %+v", resp.Data)
+	}
+}
+
+func TestHandleListUsersRejectsInvalidQuery(t *testing.T) {
+	_, handler := newTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/users?sort=not-a-field", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("sort=not-a-field: status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+// containsRel reports whether link (an RFC 5988 Link header value) has an
+// entry with rel=rel.
+func containsRel(link, rel string) bool {
+	return strings.Contains(link, `rel="`+rel+`"`)
+}
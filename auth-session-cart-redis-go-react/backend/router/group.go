@@ -0,0 +1,37 @@
+package router
+
+import "net/http"
+
+// Group batches routes under a common path prefix and middleware chain, e.g.
+//
+//	users := rt.Group("/users").Use(authMiddleware)
+//	users.POST("", createUser)
+//	users.DELETE("/{id:int}", deleteUser)
+type Group struct {
+	rt     *Router
+	prefix string
+	mw     []Middleware
+}
+
+// Use appends mw to the middleware every route in the group runs, in
+// registration order (first registered runs outermost).
+func (g *Group) Use(mw ...Middleware) *Group {
+	g.mw = append(g.mw, mw...)
+	return g
+}
+
+func (g *Group) GET(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	g.rt.GET(g.prefix+pattern, h, append(append([]Middleware{}, g.mw...), mw...)...)
+}
+
+func (g *Group) POST(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	g.rt.POST(g.prefix+pattern, h, append(append([]Middleware{}, g.mw...), mw...)...)
+}
+
+func (g *Group) PUT(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	g.rt.PUT(g.prefix+pattern, h, append(append([]Middleware{}, g.mw...), mw...)...)
+}
+
+func (g *Group) DELETE(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	g.rt.DELETE(g.prefix+pattern, h, append(append([]Middleware{}, g.mw...), mw...)...)
+}
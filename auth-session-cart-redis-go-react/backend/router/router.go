@@ -0,0 +1,181 @@
+// Package router is a small HTTP router that understands path parameters
+// and method dispatch. It replaces the http.ServeMux-based routing that
+// used to strip "/users/" prefixes by hand and misrouted "/users/search"
+// whenever registration order changed: routes are stored in a segment tree
+// where a literal segment ("search") always wins over a parameter segment
+// ("{id}") at the same position, so the two can never shadow each other.
+package router
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Middleware wraps a handler to run code before/after it, e.g. auth checks.
+type Middleware func(http.HandlerFunc) http.HandlerFunc
+
+// paramKind constrains what a path parameter is allowed to match.
+type paramKind int
+
+const (
+	paramString paramKind = iota
+	paramInt
+)
+
+type node struct {
+	static map[string]*node
+	param  *node
+	name   string
+	kind   paramKind
+
+	handlers map[string]http.HandlerFunc
+}
+
+func newNode() *node {
+	return &node{static: map[string]*node{}}
+}
+
+// Router dispatches requests by method and path, matching the literal
+// segments of registered routes before falling back to typed parameters.
+type Router struct {
+	root *node
+}
+
+// New returns an empty Router.
+func New() *Router {
+	return &Router{root: newNode()}
+}
+
+// Handle registers h for method and pattern. Segments of pattern written as
+// "{name}" or "{name:int}" are path parameters; read them with Param.
+func (rt *Router) Handle(method, pattern string, h http.HandlerFunc, mw ...Middleware) {
+	n := rt.root
+	for _, seg := range splitPath(pattern) {
+		if name, kind, ok := parseParam(seg); ok {
+			if n.param == nil {
+				n.param = newNode()
+			}
+			n.param.name = name
+			n.param.kind = kind
+			n = n.param
+			continue
+		}
+		child, ok := n.static[seg]
+		if !ok {
+			child = newNode()
+			n.static[seg] = child
+		}
+		n = child
+	}
+
+	if n.handlers == nil {
+		n.handlers = map[string]http.HandlerFunc{}
+	}
+	n.handlers[method] = applyMiddleware(h, mw)
+}
+
+// GET, POST, PUT and DELETE register a handler for pattern under the
+// matching HTTP method. mw runs only for this route, innermost-last.
+func (rt *Router) GET(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	rt.Handle(http.MethodGet, pattern, h, mw...)
+}
+
+func (rt *Router) POST(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	rt.Handle(http.MethodPost, pattern, h, mw...)
+}
+
+func (rt *Router) PUT(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	rt.Handle(http.MethodPut, pattern, h, mw...)
+}
+
+func (rt *Router) DELETE(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	rt.Handle(http.MethodDelete, pattern, h, mw...)
+}
+
+// Group returns a builder that prefixes every route registered through it
+// with prefix and runs shared middleware before each one.
+func (rt *Router) Group(prefix string) *Group {
+	return &Group{rt: rt, prefix: strings.TrimSuffix(prefix, "/")}
+}
+
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := rt.root
+	params := paramMap{}
+
+	for _, seg := range splitPath(r.URL.Path) {
+		if child, ok := n.static[seg]; ok {
+			n = child
+			continue
+		}
+		if n.param != nil {
+			if n.param.kind == paramInt {
+				if _, err := strconv.Atoi(seg); err != nil {
+					http.NotFound(w, r)
+					return
+				}
+			}
+			params[n.param.name] = seg
+			n = n.param
+			continue
+		}
+		http.NotFound(w, r)
+		return
+	}
+
+	if n.handlers == nil {
+		http.NotFound(w, r)
+		return
+	}
+	h, ok := n.handlers[r.Method]
+	if !ok {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	h(w, r.WithContext(context.WithValue(r.Context(), paramsContextKey, params)))
+}
+
+func applyMiddleware(h http.HandlerFunc, mw []Middleware) http.HandlerFunc {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// parseParam reports whether seg is a "{name}" or "{name:int}" parameter
+// segment, and if so returns its name and kind.
+func parseParam(seg string) (name string, kind paramKind, ok bool) {
+	if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+		return "", 0, false
+	}
+	inner := seg[1 : len(seg)-1]
+	if idx := strings.IndexByte(inner, ':'); idx != -1 {
+		if inner[idx+1:] == "int" {
+			return inner[:idx], paramInt, true
+		}
+		return inner[:idx], paramString, true
+	}
+	return inner, paramString, true
+}
+
+type contextKey int
+
+const paramsContextKey contextKey = 0
+
+type paramMap map[string]string
+
+// Param returns the value path parameter name matched for r, or "" if there
+// is no such parameter.
+func Param(r *http.Request, name string) string {
+	params, _ := r.Context().Value(paramsContextKey).(paramMap)
+	return params[name]
+}
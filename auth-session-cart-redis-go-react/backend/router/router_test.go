@@ -0,0 +1,101 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearchDoesNotConflictWithIDParam(t *testing.T) {
+	rt := New()
+	var gotSearch, gotID bool
+
+	// Deliberately register the param route first: a prefix-based router
+	// would let "/users/{id}" swallow "/users/search" depending on this
+	// order. This router must not.
+	rt.GET("/users/{id:int}", func(w http.ResponseWriter, r *http.Request) {
+		gotID = true
+	})
+	rt.GET("/users/search", func(w http.ResponseWriter, r *http.Request) {
+		gotSearch = true
+	})
+
+	rt.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/search", nil))
+	if !gotSearch || gotID {
+		t.Fatalf("GET /users/search: gotSearch=%v gotID=%v, want gotSearch=true gotID=false", gotSearch, gotID)
+	}
+
+	gotSearch, gotID = false, false
+	rt.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	if gotSearch || !gotID {
+		t.Fatalf("GET /users/42: gotSearch=%v gotID=%v, want gotSearch=false gotID=true", gotSearch, gotID)
+	}
+}
+
+func TestIntParamRejectsNonNumeric(t *testing.T) {
+	rt := New()
+	rt.GET("/users/{id:int}", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not run for a non-numeric id")
+	})
+
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/not-a-number", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestParamExtraction(t *testing.T) {
+	rt := New()
+	var got string
+	rt.GET("/users/{id:int}", func(w http.ResponseWriter, r *http.Request) {
+		got = Param(r, "id")
+	})
+
+	rt.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/7", nil))
+	if got != "7" {
+		t.Fatalf("Param(id) = %q, want %q", got, "7")
+	}
+}
+
+func TestMethodMismatchIsNotAllowed(t *testing.T) {
+	rt := New()
+	rt.GET("/users", func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestGroupAppliesMiddlewareAndPrefix(t *testing.T) {
+	rt := New()
+	var order []string
+
+	mw := func(name string) Middleware {
+		return func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next(w, r)
+			}
+		}
+	}
+
+	g := rt.Group("/users").Use(mw("outer"), mw("inner"))
+	g.DELETE("/{id:int}", func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	})
+
+	rt.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/users/3", nil))
+
+	want := []string{"outer", "inner", "handler"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
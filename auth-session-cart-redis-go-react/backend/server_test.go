@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thaolaptrinh/dev-lab/auth-session-cart-redis-go-react/backend/auth"
+	"github.com/thaolaptrinh/dev-lab/auth-session-cart-redis-go-react/backend/events"
+	"github.com/thaolaptrinh/dev-lab/auth-session-cart-redis-go-react/backend/storage"
+)
+
+// newTestServer builds a server wired the same way main() does, backed by a
+// fresh in-memory repository seeded with the prototype's two fixture users
+// (thao@example.com, admin; mai@example.com, user; both password123), and
+// returns it alongside the http.Handler tests should drive with httptest.
+func newTestServer(t *testing.T) (*server, http.Handler) {
+	t.Helper()
+
+	repo, err := storage.Open(storage.Config{})
+	if err != nil {
+		t.Fatalf("storage.Open: %v", err)
+	}
+	s := &server{
+		repo:        repo,
+		issuer:      auth.NewIssuer("test-secret", 15*time.Minute, 7*24*time.Hour),
+		events:      events.NewBus(),
+		idempotency: newIdempotencyCache(),
+	}
+	return s, s.newRouter()
+}
+
+// doJSON sends method/path with body (nil for none) and an optional bearer
+// token, decoding the JSON response into out if it's non-nil.
+func doJSON(t *testing.T, handler http.Handler, method, path, token string, body interface{}, out interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal request body: %v", err)
+		}
+		reader = bytes.NewReader(b)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode response body %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec
+}
+
+// loginAs logs in as email/password123 (the seeded fixture password) and
+// returns its access token.
+func loginAs(t *testing.T, handler http.Handler, email string) string {
+	t.Helper()
+
+	var resp struct {
+		Data auth.TokenPair `json:"data"`
+	}
+	rec := doJSON(t, handler, http.MethodPost, "/auth/login", "", LoginRequest{Email: email, Password: "password123"}, &resp)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login as %s: status = %d, body = %s", email, rec.Code, rec.Body.String())
+	}
+	return resp.Data.AccessToken
+}
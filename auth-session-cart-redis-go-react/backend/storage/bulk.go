@@ -0,0 +1,48 @@
+package storage
+
+import "errors"
+
+// BulkOpKind is the kind of mutation a BulkOp performs.
+type BulkOpKind string
+
+const (
+	BulkCreate BulkOpKind = "create"
+	BulkUpdate BulkOpKind = "update"
+	BulkDelete BulkOpKind = "delete"
+)
+
+// BulkOp is one fully-resolved operation in a Bulk call. Callers build User
+// the same way they would for Create/Update (ID is ignored for Create).
+type BulkOp struct {
+	Kind BulkOpKind
+	ID   int
+	User User
+}
+
+// BulkItemResult is the outcome of one BulkOp, in the same order as the
+// ops passed to Bulk.
+type BulkItemResult struct {
+	Index int
+	User  User
+	Err   error
+}
+
+// ErrBatchRolledBack is reported for ops that succeeded individually but
+// were undone because Bulk was called with atomic=true and another op in
+// the same batch failed (whether it came before or after them).
+var ErrBatchRolledBack = errors.New("storage: batch rolled back")
+
+// rollBackResults replaces every successful result with ErrBatchRolledBack,
+// leaving the result that actually failed untouched, and is used by Bulk
+// implementations once they've decided an atomic batch must be undone.
+func rollBackResults(results []BulkItemResult) []BulkItemResult {
+	out := make([]BulkItemResult, len(results))
+	for i, res := range results {
+		if res.Err == nil {
+			res = BulkItemResult{Index: res.Index}
+			res.Err = ErrBatchRolledBack
+		}
+		out[i] = res
+	}
+	return out
+}
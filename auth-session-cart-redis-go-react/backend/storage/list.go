@@ -0,0 +1,40 @@
+package storage
+
+// SortField restricts List results to the whitelisted sortable columns.
+type SortField string
+
+const (
+	SortByName      SortField = "name"
+	SortByAge       SortField = "age"
+	SortByCreatedAt SortField = "created_at"
+)
+
+// SortOrder is ascending or descending.
+type SortOrder string
+
+const (
+	OrderAsc  SortOrder = "asc"
+	OrderDesc SortOrder = "desc"
+)
+
+// ListOptions filters, sorts and paginates List. Page/PerPage are
+// 1-indexed; PerPage <= 0 means "no limit", which internal callers like
+// stats use to fetch every matching row in one pass.
+type ListOptions struct {
+	Page    int
+	PerPage int
+
+	SortBy SortField
+	Order  SortOrder
+
+	MinAge      int
+	MaxAge      int
+	EmailDomain string
+}
+
+// ListResult is the page of users List returns, plus the total number of
+// rows matching the filters before pagination was applied.
+type ListResult struct {
+	Users []User
+	Total int
+}
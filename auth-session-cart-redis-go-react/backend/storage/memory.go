@@ -0,0 +1,284 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// seedPasswordHash is the bcrypt hash of "password123", used for both
+// fixture accounts below so local dev and tests have a known login.
+const seedPasswordHash = "$2a$10$0z/ZpgLzboXWUQDN77HIaOh0/mYiNYW0ghkkxuhCdGAH0wGqB3ofa"
+
+// defaultUsers mirrors the two fixture accounts the original prototype
+// started with, so the memory driver's behaviour doesn't change. Thao is
+// seeded as admin so there is always someone who can manage other accounts.
+func defaultUsers() []User {
+	return []User{
+		{ID: 1, Name: "Nguyen Van Thao", Email: "thao@example.com", Age: 25, Role: RoleAdmin, PasswordHash: seedPasswordHash, CreatedAt: time.Now()},
+		{ID: 2, Name: "Tran Thi Mai", Email: "mai@example.com", Age: 30, Role: RoleUser, PasswordHash: seedPasswordHash, CreatedAt: time.Now()},
+	}
+}
+
+// MemoryRepository is an in-process UserRepository backed by a slice. It is
+// the default driver and what the original prototype used, minus the data
+// race: every access goes through mu.
+type MemoryRepository struct {
+	mu     sync.Mutex
+	users  []User
+	nextID int
+}
+
+// NewMemoryRepository returns an empty in-memory repository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{nextID: 1}
+}
+
+// SeedMemoryRepository returns an in-memory repository pre-populated with
+// seed, mirroring the fixture users the old main.go started with.
+func SeedMemoryRepository(seed []User) *MemoryRepository {
+	r := NewMemoryRepository()
+	for _, u := range seed {
+		if u.ID >= r.nextID {
+			r.nextID = u.ID + 1
+		}
+		r.users = append(r.users, u)
+	}
+	return r
+}
+
+func (r *MemoryRepository) indexByID(id int) int {
+	for i, u := range r.users {
+		if u.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *MemoryRepository) indexByEmail(email string, excludeID int) int {
+	for i, u := range r.users {
+		if u.Email == email && u.ID != excludeID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, u User) (User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.create(u)
+}
+
+// create is Create's logic without the lock, so Bulk can run a whole batch
+// under a single critical section.
+func (r *MemoryRepository) create(u User) (User, error) {
+	if r.indexByEmail(u.Email, 0) != -1 {
+		return User{}, ErrEmailTaken
+	}
+
+	u.ID = r.nextID
+	r.nextID++
+	r.users = append(r.users, u)
+	return u, nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, id int) (User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexByID(id)
+	if i == -1 {
+		return User{}, ErrNotFound
+	}
+	return r.users[i], nil
+}
+
+func (r *MemoryRepository) GetByEmail(ctx context.Context, email string) (User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexByEmail(email, 0)
+	if i == -1 {
+		return User{}, ErrNotFound
+	}
+	return r.users[i], nil
+}
+
+func (r *MemoryRepository) Update(ctx context.Context, u User) (User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.update(u)
+}
+
+// update is Update's logic without the lock; see create.
+func (r *MemoryRepository) update(u User) (User, error) {
+	i := r.indexByID(u.ID)
+	if i == -1 {
+		return User{}, ErrNotFound
+	}
+	if u.Email != "" && r.indexByEmail(u.Email, u.ID) != -1 {
+		return User{}, ErrEmailTaken
+	}
+
+	r.users[i] = u
+	return r.users[i], nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.delete(id)
+}
+
+// delete is Delete's logic without the lock; see create.
+func (r *MemoryRepository) delete(id int) error {
+	i := r.indexByID(id)
+	if i == -1 {
+		return ErrNotFound
+	}
+	r.users = append(r.users[:i], r.users[i+1:]...)
+	return nil
+}
+
+func (r *MemoryRepository) List(ctx context.Context, opts ListOptions) (ListResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	filtered := make([]User, 0, len(r.users))
+	for _, u := range r.users {
+		if opts.MinAge > 0 && u.Age < opts.MinAge {
+			continue
+		}
+		if opts.MaxAge > 0 && u.Age > opts.MaxAge {
+			continue
+		}
+		if opts.EmailDomain != "" && !strings.HasSuffix(u.Email, "@"+opts.EmailDomain) {
+			continue
+		}
+		filtered = append(filtered, u)
+	}
+
+	sortUsers(filtered, opts.SortBy, opts.Order)
+
+	total := len(filtered)
+	start, end := paginationBounds(opts.Page, opts.PerPage, total)
+	page := make([]User, end-start)
+	copy(page, filtered[start:end])
+
+	return ListResult{Users: page, Total: total}, nil
+}
+
+func sortUsers(users []User, field SortField, order SortOrder) {
+	less := func(i, j int) bool {
+		switch field {
+		case SortByAge:
+			return users[i].Age < users[j].Age
+		case SortByCreatedAt:
+			return users[i].CreatedAt.Before(users[j].CreatedAt)
+		default: // SortByName and the zero value
+			return strings.ToLower(users[i].Name) < strings.ToLower(users[j].Name)
+		}
+	}
+	sort.SliceStable(users, func(i, j int) bool {
+		if order == OrderDesc {
+			return less(j, i)
+		}
+		return less(i, j)
+	})
+}
+
+// paginationBounds returns the [start, end) slice bounds for page/perPage
+// over total rows. perPage <= 0 means "return everything".
+func paginationBounds(page, perPage, total int) (start, end int) {
+	if perPage <= 0 {
+		return 0, total
+	}
+	if page < 1 {
+		page = 1
+	}
+	start = (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end = start + perPage
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+func (r *MemoryRepository) Search(ctx context.Context, name string) ([]User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []User
+	needle := strings.ToLower(name)
+	for _, u := range r.users {
+		if strings.Contains(strings.ToLower(u.Name), needle) {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+func (r *MemoryRepository) DisableUser(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexByID(id)
+	if i == -1 {
+		return ErrNotFound
+	}
+	r.users[i].IsDisabled = true
+	return nil
+}
+
+// Bulk runs ops under a single lock so the batch is consistent from every
+// other caller's point of view. When atomic is true, the pre-batch slice is
+// snapshotted up front and restored if any op fails.
+func (r *MemoryRepository) Bulk(ctx context.Context, ops []BulkOp, atomic bool) ([]BulkItemResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var snapshot []User
+	snapshotNextID := r.nextID
+	if atomic {
+		snapshot = make([]User, len(r.users))
+		copy(snapshot, r.users)
+	}
+
+	results := make([]BulkItemResult, len(ops))
+	failed := false
+	for i, op := range ops {
+		var user User
+		var err error
+		switch op.Kind {
+		case BulkCreate:
+			user, err = r.create(op.User)
+		case BulkUpdate:
+			user, err = r.update(op.User)
+		case BulkDelete:
+			err = r.delete(op.ID)
+		default:
+			err = fmt.Errorf("storage: unknown bulk op %q", op.Kind)
+		}
+		if err != nil {
+			failed = true
+			results[i] = BulkItemResult{Index: i, Err: err}
+			continue
+		}
+		results[i] = BulkItemResult{Index: i, User: user}
+	}
+
+	if failed && atomic {
+		r.users = snapshot
+		r.nextID = snapshotNextID
+		return rollBackResults(results), nil
+	}
+	return results, nil
+}
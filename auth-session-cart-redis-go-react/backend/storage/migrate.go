@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+)
+
+//go:embed migrations
+var migrationFS embed.FS
+
+// schemaMigrationsDDL creates the bookkeeping table Migrate uses to record
+// which migration files have already been applied, so a later migration
+// that isn't itself idempotent (e.g. ALTER TABLE ... ADD COLUMN) is still
+// safe to run on every startup.
+const schemaMigrationsDDL = `CREATE TABLE IF NOT EXISTS schema_migrations (name TEXT PRIMARY KEY)`
+
+// Migrate applies every embedded *.up.sql file for driver (postgres or
+// sqlite3), in filename order, skipping ones already recorded as applied.
+func Migrate(db *sql.DB, driver string) error {
+	if _, err := db.Exec(schemaMigrationsDDL); err != nil {
+		return fmt.Errorf("storage: create schema_migrations: %w", err)
+	}
+
+	dir := "migrations/" + driver
+	entries, err := migrationFS.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("storage: no migrations for driver %q: %w", driver, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	placeholder := placeholderFor(driver)
+	for _, name := range names {
+		applied, err := isMigrationApplied(db, name, placeholder)
+		if err != nil {
+			return err
+		}
+		if applied {
+			continue
+		}
+
+		sqlBytes, err := migrationFS.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("storage: read migration %s: %w", name, err)
+		}
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("storage: begin migration %s: %w", name, err)
+		}
+		if _, err := tx.Exec(string(sqlBytes)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("storage: apply migration %s: %w", name, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (name) VALUES (`+placeholder+`)`, name); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("storage: record migration %s: %w", name, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("storage: commit migration %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func isMigrationApplied(db *sql.DB, name, placeholder string) (bool, error) {
+	var found string
+	err := db.QueryRow(`SELECT name FROM schema_migrations WHERE name = `+placeholder, name).Scan(&found)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("storage: check migration %s: %w", name, err)
+	default:
+		return true, nil
+	}
+}
+
+// placeholderFor returns the bind-variable placeholder for driver ("$1" for
+// postgres, "?" otherwise).
+func placeholderFor(driver string) string {
+	if driver == "postgres" {
+		return "$1"
+	}
+	return "?"
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Config selects which UserRepository Open builds.
+type Config struct {
+	// Driver is "memory" (default), "postgres" or "sqlite3".
+	Driver string
+	// DSN is the database/sql data source name. Required for postgres and
+	// sqlite3, ignored for memory.
+	DSN string
+}
+
+// Open builds the UserRepository described by cfg. For the memory driver it
+// seeds the two fixture users the prototype shipped with, so a fresh
+// `STORAGE_DRIVER=memory` run behaves like before.
+func Open(cfg Config) (UserRepository, error) {
+	switch cfg.Driver {
+	case "", "memory":
+		return SeedMemoryRepository(defaultUsers()), nil
+
+	case "postgres", "sqlite3":
+		if cfg.DSN == "" {
+			return nil, fmt.Errorf("storage: DATABASE_URL is required for driver %q", cfg.Driver)
+		}
+		db, err := sql.Open(cfg.Driver, cfg.DSN)
+		if err != nil {
+			return nil, fmt.Errorf("storage: open %s: %w", cfg.Driver, err)
+		}
+		if err := db.Ping(); err != nil {
+			return nil, fmt.Errorf("storage: ping %s: %w", cfg.Driver, err)
+		}
+		if err := Migrate(db, cfg.Driver); err != nil {
+			return nil, err
+		}
+		return NewSQLRepository(db, cfg.Driver)
+
+	default:
+		return nil, fmt.Errorf("storage: unknown STORAGE_DRIVER %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,334 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SQLRepository is a UserRepository backed by database/sql. It works against
+// any driver that speaks standard SQL for the users table created by
+// Migrate; placeholder style (`$1` vs `?`) is the only thing that differs
+// between Postgres and SQLite, so it is abstracted behind bindVar.
+type SQLRepository struct {
+	db      *sql.DB
+	bindVar func(n int) string
+}
+
+const userColumns = "id, name, email, age, password_hash, role, is_disabled, created_at"
+
+// execer is satisfied by both *sql.DB and *sql.Tx, letting Create/Get/
+// Update/Delete run either directly against the database or inside the
+// transaction Bulk opens.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// NewSQLRepository wraps db as a UserRepository. driver must be "postgres"
+// or "sqlite3" so the repository knows which placeholder style to emit.
+func NewSQLRepository(db *sql.DB, driver string) (*SQLRepository, error) {
+	var bindVar func(n int) string
+	switch driver {
+	case "postgres":
+		bindVar = func(n int) string { return fmt.Sprintf("$%d", n) }
+	case "sqlite3":
+		bindVar = func(n int) string { return "?" }
+	default:
+		return nil, fmt.Errorf("storage: unsupported driver %q", driver)
+	}
+	return &SQLRepository{db: db, bindVar: bindVar}, nil
+}
+
+func (r *SQLRepository) Create(ctx context.Context, u User) (User, error) {
+	return r.create(ctx, r.db, u)
+}
+
+func (r *SQLRepository) create(ctx context.Context, q execer, u User) (User, error) {
+	query := fmt.Sprintf(
+		`INSERT INTO users (name, email, age, password_hash, role, is_disabled, created_at) VALUES (%s, %s, %s, %s, %s, %s, %s) RETURNING id, created_at`,
+		r.bindVar(1), r.bindVar(2), r.bindVar(3), r.bindVar(4), r.bindVar(5), r.bindVar(6), r.bindVar(7),
+	)
+	createdAt := u.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	row := q.QueryRowContext(ctx, query, u.Name, u.Email, u.Age, u.PasswordHash, u.Role, u.IsDisabled, createdAt)
+	if err := row.Scan(&u.ID, &u.CreatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return User{}, ErrEmailTaken
+		}
+		return User{}, fmt.Errorf("storage: create user: %w", err)
+	}
+	return u, nil
+}
+
+func (r *SQLRepository) Get(ctx context.Context, id int) (User, error) {
+	return r.get(ctx, r.db, id)
+}
+
+func (r *SQLRepository) get(ctx context.Context, q execer, id int) (User, error) {
+	query := fmt.Sprintf(`SELECT %s FROM users WHERE id = %s`, userColumns, r.bindVar(1))
+	var u User
+	err := q.QueryRowContext(ctx, query, id).Scan(&u.ID, &u.Name, &u.Email, &u.Age, &u.PasswordHash, &u.Role, &u.IsDisabled, &u.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, ErrNotFound
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("storage: get user: %w", err)
+	}
+	return u, nil
+}
+
+func (r *SQLRepository) GetByEmail(ctx context.Context, email string) (User, error) {
+	query := fmt.Sprintf(`SELECT %s FROM users WHERE email = %s`, userColumns, r.bindVar(1))
+	var u User
+	err := r.db.QueryRowContext(ctx, query, email).Scan(&u.ID, &u.Name, &u.Email, &u.Age, &u.PasswordHash, &u.Role, &u.IsDisabled, &u.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, ErrNotFound
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("storage: get user by email: %w", err)
+	}
+	return u, nil
+}
+
+func (r *SQLRepository) Update(ctx context.Context, u User) (User, error) {
+	return r.update(ctx, r.db, u)
+}
+
+func (r *SQLRepository) update(ctx context.Context, q execer, u User) (User, error) {
+	query := fmt.Sprintf(
+		`UPDATE users SET name = %s, email = %s, age = %s WHERE id = %s`,
+		r.bindVar(1), r.bindVar(2), r.bindVar(3), r.bindVar(4),
+	)
+	res, err := q.ExecContext(ctx, query, u.Name, u.Email, u.Age, u.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return User{}, ErrEmailTaken
+		}
+		return User{}, fmt.Errorf("storage: update user: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return User{}, ErrNotFound
+	}
+	return r.get(ctx, q, u.ID)
+}
+
+func (r *SQLRepository) Delete(ctx context.Context, id int) error {
+	return r.delete(ctx, r.db, id)
+}
+
+func (r *SQLRepository) delete(ctx context.Context, q execer, id int) error {
+	query := fmt.Sprintf(`DELETE FROM users WHERE id = %s`, r.bindVar(1))
+	res, err := q.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("storage: delete user: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLRepository) List(ctx context.Context, opts ListOptions) (ListResult, error) {
+	where, args := r.filterClause(opts)
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM users%s`, where)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return ListResult{}, fmt.Errorf("storage: count users: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM users%s ORDER BY %s`, userColumns, where, orderClause(opts))
+	if opts.PerPage > 0 {
+		page := opts.Page
+		if page < 1 {
+			page = 1
+		}
+		args = append(args, opts.PerPage, (page-1)*opts.PerPage)
+		query += fmt.Sprintf(" LIMIT %s OFFSET %s", r.bindVar(len(args)-1), r.bindVar(len(args)))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return ListResult{}, fmt.Errorf("storage: list users: %w", err)
+	}
+	defer rows.Close()
+
+	users, err := scanUsers(rows)
+	if err != nil {
+		return ListResult{}, err
+	}
+	return ListResult{Users: users, Total: total}, nil
+}
+
+// filterClause builds the "WHERE ..." clause (and its bind args) for the
+// age/email-domain filters in opts. Returns "" and nil args when nothing is
+// filtered.
+func (r *SQLRepository) filterClause(opts ListOptions) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+
+	if opts.MinAge > 0 {
+		args = append(args, opts.MinAge)
+		clauses = append(clauses, "age >= "+r.bindVar(len(args)))
+	}
+	if opts.MaxAge > 0 {
+		args = append(args, opts.MaxAge)
+		clauses = append(clauses, "age <= "+r.bindVar(len(args)))
+	}
+	if opts.EmailDomain != "" {
+		args = append(args, "%@"+escapeLikePattern(opts.EmailDomain))
+		clauses = append(clauses, "email LIKE "+r.bindVar(len(args))+` ESCAPE '\'`)
+	}
+
+	if len(clauses) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(clauses, " AND "), args
+}
+
+// likeEscaper escapes the characters LIKE treats specially (%, _) plus the
+// escape character itself (\), so filterClause's EmailDomain match behaves
+// like MemoryRepository's literal strings.HasSuffix instead of a wildcard
+// pattern. Must stay in sync with the `ESCAPE '\'` clause above.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLikePattern(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// orderClause maps the whitelisted SortField/SortOrder to a safe "ORDER BY"
+// fragment; it never interpolates caller input directly into SQL.
+func orderClause(opts ListOptions) string {
+	column := "name"
+	switch opts.SortBy {
+	case SortByAge:
+		column = "age"
+	case SortByCreatedAt:
+		column = "created_at"
+	}
+
+	direction := "ASC"
+	if opts.Order == OrderDesc {
+		direction = "DESC"
+	}
+
+	return column + " " + direction
+}
+
+func (r *SQLRepository) Search(ctx context.Context, name string) ([]User, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM users WHERE LOWER(name) LIKE %s ORDER BY id`,
+		userColumns, r.bindVar(1),
+	)
+	rows, err := r.db.QueryContext(ctx, query, "%"+strings.ToLower(name)+"%")
+	if err != nil {
+		return nil, fmt.Errorf("storage: search users: %w", err)
+	}
+	defer rows.Close()
+	return scanUsers(rows)
+}
+
+func (r *SQLRepository) DisableUser(ctx context.Context, id int) error {
+	query := fmt.Sprintf(`UPDATE users SET is_disabled = true WHERE id = %s`, r.bindVar(1))
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("storage: disable user: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Bulk runs ops inside one transaction, using a SAVEPOINT per op so a
+// failing op can be undone without poisoning the rest of the transaction
+// (Postgres aborts the whole transaction after any error otherwise). When
+// atomic is true and any op failed, the whole transaction is rolled back
+// instead of committed.
+func (r *SQLRepository) Bulk(ctx context.Context, ops []BulkOp, atomic bool) ([]BulkItemResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("storage: begin bulk transaction: %w", err)
+	}
+
+	results := make([]BulkItemResult, len(ops))
+	failed := false
+
+	for i, op := range ops {
+		savepoint := fmt.Sprintf("bulk_op_%d", i)
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("storage: create savepoint: %w", err)
+		}
+
+		var user User
+		var opErr error
+		switch op.Kind {
+		case BulkCreate:
+			user, opErr = r.create(ctx, tx, op.User)
+		case BulkUpdate:
+			user, opErr = r.update(ctx, tx, op.User)
+		case BulkDelete:
+			opErr = r.delete(ctx, tx, op.ID)
+		default:
+			opErr = fmt.Errorf("storage: unknown bulk op %q", op.Kind)
+		}
+
+		if opErr != nil {
+			failed = true
+			results[i] = BulkItemResult{Index: i, Err: opErr}
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("storage: rollback to savepoint: %w", err)
+			}
+			continue
+		}
+
+		results[i] = BulkItemResult{Index: i, User: user}
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("storage: release savepoint: %w", err)
+		}
+	}
+
+	if failed && atomic {
+		if err := tx.Rollback(); err != nil {
+			return nil, fmt.Errorf("storage: rollback bulk transaction: %w", err)
+		}
+		return rollBackResults(results), nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("storage: commit bulk transaction: %w", err)
+	}
+	return results, nil
+}
+
+func scanUsers(rows *sql.Rows) ([]User, error) {
+	var out []User
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Age, &u.PasswordHash, &u.Role, &u.IsDisabled, &u.CreatedAt); err != nil {
+			return nil, fmt.Errorf("storage: scan user: %w", err)
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// isUniqueViolation reports whether err looks like a unique-constraint
+// failure from either the Postgres or SQLite driver, without importing
+// either driver package (that would defeat the point of being driver
+// agnostic here).
+func isUniqueViolation(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "unique") || strings.Contains(msg, "UNIQUE") || strings.Contains(msg, "duplicate key")
+}
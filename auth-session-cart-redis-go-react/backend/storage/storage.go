@@ -0,0 +1,55 @@
+// Package storage hides how User records are persisted behind the
+// UserRepository interface, so handlers never touch a slice or a SQL driver
+// directly.
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// User is the persisted representation of an account.
+type User struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	Age          int       `json:"age"`
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+	IsDisabled   bool      `json:"is_disabled"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Role values recognised by the server's requireAdmin/requireSelfOrAdmin
+// middleware.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// ErrNotFound is returned when a lookup by ID finds no matching user.
+var ErrNotFound = errors.New("storage: user not found")
+
+// ErrEmailTaken is returned by Create/Update when the email is already used
+// by another user.
+var ErrEmailTaken = errors.New("storage: email already exists")
+
+// UserRepository is the persistence boundary for User records.
+// Implementations must be safe for concurrent use.
+type UserRepository interface {
+	Create(ctx context.Context, u User) (User, error)
+	Get(ctx context.Context, id int) (User, error)
+	GetByEmail(ctx context.Context, email string) (User, error)
+	Update(ctx context.Context, u User) (User, error)
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context, opts ListOptions) (ListResult, error)
+	Search(ctx context.Context, name string) ([]User, error)
+	DisableUser(ctx context.Context, id int) error
+
+	// Bulk applies ops in order, returning one BulkItemResult per op rather
+	// than aborting on the first failure. When atomic is true, any failing
+	// op rolls back every other op in the same call (their results report
+	// ErrBatchRolledBack instead of success).
+	Bulk(ctx context.Context, ops []BulkOp, atomic bool) ([]BulkItemResult, error)
+}
@@ -0,0 +1,241 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+// newSQLiteRepo opens an in-memory sqlite3-backed SQLRepository, migrated
+// and ready to use. cache=shared plus a single open connection keeps every
+// statement on the same in-memory database; sqlite3 otherwise hands out a
+// fresh (empty) database per connection.
+func newSQLiteRepo(t *testing.T) *SQLRepository {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file::memory:?cache=shared")
+	if err != nil {
+		t.Fatalf("open sqlite3: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	if err := Migrate(db, "sqlite3"); err != nil {
+		t.Fatalf("migrate sqlite3: %v", err)
+	}
+
+	repo, err := NewSQLRepository(db, "sqlite3")
+	if err != nil {
+		t.Fatalf("NewSQLRepository: %v", err)
+	}
+	return repo
+}
+
+// repoCases returns a fresh instance of every UserRepository backend this
+// package ships, keyed by driver name, so CRUD/list/bulk behaviour is
+// asserted once per case rather than duplicated per driver.
+func repoCases(t *testing.T) map[string]UserRepository {
+	t.Helper()
+	return map[string]UserRepository{
+		"memory":  NewMemoryRepository(),
+		"sqlite3": newSQLiteRepo(t),
+	}
+}
+
+func TestRepositoryCreateGetUpdateDelete(t *testing.T) {
+	for name, repo := range repoCases(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			created, err := repo.Create(ctx, User{Name: "Le Van A", Email: "a@example.com", Age: 20})
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if created.ID == 0 {
+				t.Fatal("Create did not assign an ID")
+			}
+
+			got, err := repo.Get(ctx, created.ID)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if got.Email != "a@example.com" {
+				t.Fatalf("Get email = %q, want a@example.com", got.Email)
+			}
+
+			if _, err := repo.Create(ctx, User{Name: "Dup", Email: "a@example.com", Age: 21}); !errors.Is(err, ErrEmailTaken) {
+				t.Fatalf("Create with duplicate email: err = %v, want ErrEmailTaken", err)
+			}
+
+			got.Name = "Le Van A Updated"
+			got.Age = 22
+			updated, err := repo.Update(ctx, got)
+			if err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			if updated.Name != "Le Van A Updated" || updated.Age != 22 {
+				t.Fatalf("Update did not persist: %+v", updated)
+			}
+
+			if err := repo.Delete(ctx, created.ID); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if _, err := repo.Get(ctx, created.ID); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get after Delete: err = %v, want ErrNotFound", err)
+			}
+			if err := repo.Delete(ctx, created.ID); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Delete twice: err = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestRepositoryListPaginationFilterSort(t *testing.T) {
+	for name, repo := range repoCases(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			seed := []User{
+				{Name: "Charlie", Email: "charlie@foo.com", Age: 40},
+				{Name: "Alice", Email: "alice@bar.com", Age: 20},
+				{Name: "Bob", Email: "bob@foo.com", Age: 30},
+			}
+			for _, u := range seed {
+				if _, err := repo.Create(ctx, u); err != nil {
+					t.Fatalf("Create %s: %v", u.Name, err)
+				}
+			}
+
+			res, err := repo.List(ctx, ListOptions{SortBy: SortByName, Order: OrderAsc})
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if res.Total != 3 {
+				t.Fatalf("Total = %d, want 3", res.Total)
+			}
+			wantOrder := []string{"Alice", "Bob", "Charlie"}
+			for i, name := range wantOrder {
+				if res.Users[i].Name != name {
+					t.Fatalf("Users[%d] = %q, want %q", i, res.Users[i].Name, name)
+				}
+			}
+
+			res, err = repo.List(ctx, ListOptions{EmailDomain: "foo.com", SortBy: SortByAge, Order: OrderDesc})
+			if err != nil {
+				t.Fatalf("List filtered: %v", err)
+			}
+			if res.Total != 2 {
+				t.Fatalf("Total filtered = %d, want 2", res.Total)
+			}
+			if res.Users[0].Name != "Charlie" || res.Users[1].Name != "Bob" {
+				t.Fatalf("filtered+sorted order = %v, want [Charlie Bob]", res.Users)
+			}
+
+			res, err = repo.List(ctx, ListOptions{Page: 2, PerPage: 2, SortBy: SortByAge, Order: OrderAsc})
+			if err != nil {
+				t.Fatalf("List paginated: %v", err)
+			}
+			if res.Total != 3 {
+				t.Fatalf("Total paginated = %d, want 3", res.Total)
+			}
+			if len(res.Users) != 1 || res.Users[0].Name != "Charlie" {
+				t.Fatalf("page 2 of 2 = %v, want [Charlie]", res.Users)
+			}
+		})
+	}
+}
+
+// TestRepositoryEmailDomainFilterTreatsUnderscoreLiterally guards the
+// equivalence between backends: MemoryRepository matches EmailDomain with a
+// literal strings.HasSuffix, so SQLRepository's "LIKE '%@'+domain" must
+// escape %/_ or a domain like "bar_com" would also match "barxcom" over
+// SQL but not over memory.
+func TestRepositoryEmailDomainFilterTreatsUnderscoreLiterally(t *testing.T) {
+	for name, repo := range repoCases(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			seed := []User{
+				{Name: "Underscore Match", Email: "a@bar_com", Age: 10},
+				{Name: "Wildcard Decoy", Email: "b@barxcom", Age: 10},
+			}
+			for _, u := range seed {
+				if _, err := repo.Create(ctx, u); err != nil {
+					t.Fatalf("Create %s: %v", u.Name, err)
+				}
+			}
+
+			res, err := repo.List(ctx, ListOptions{EmailDomain: "bar_com"})
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if res.Total != 1 || res.Users[0].Email != "a@bar_com" {
+				t.Fatalf("EmailDomain=%q matched %v, want only a@bar_com", "bar_com", res.Users)
+			}
+		})
+	}
+}
+
+func TestRepositoryBulkAtomicRollback(t *testing.T) {
+	for name, repo := range repoCases(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			existing, err := repo.Create(ctx, User{Name: "Existing", Email: "existing@example.com", Age: 18})
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+
+			ops := []BulkOp{
+				{Kind: BulkCreate, User: User{Name: "New One", Email: "new1@example.com", Age: 19}},
+				// Duplicates existing's email, so this op fails and (with
+				// atomic=true) should take the first op down with it.
+				{Kind: BulkCreate, User: User{Name: "New Two", Email: "existing@example.com", Age: 20}},
+			}
+
+			results, err := repo.Bulk(ctx, ops, true)
+			if err != nil {
+				t.Fatalf("Bulk atomic: %v", err)
+			}
+			if len(results) != 2 {
+				t.Fatalf("len(results) = %d, want 2", len(results))
+			}
+			if !errors.Is(results[0].Err, ErrBatchRolledBack) {
+				t.Fatalf("results[0].Err = %v, want ErrBatchRolledBack", results[0].Err)
+			}
+			if !errors.Is(results[1].Err, ErrEmailTaken) {
+				t.Fatalf("results[1].Err = %v, want ErrEmailTaken", results[1].Err)
+			}
+
+			if _, err := repo.GetByEmail(ctx, "new1@example.com"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("atomic rollback should have undone the first create, got err = %v", err)
+			}
+
+			list, err := repo.List(ctx, ListOptions{})
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if list.Total != 1 {
+				t.Fatalf("Total after rolled-back batch = %d, want 1 (only %q)", list.Total, existing.Email)
+			}
+
+			// Without atomic, the failing op is reported but the others commit.
+			ops = []BulkOp{
+				{Kind: BulkCreate, User: User{Name: "New One", Email: "new1@example.com", Age: 19}},
+				{Kind: BulkCreate, User: User{Name: "New Two", Email: "existing@example.com", Age: 20}},
+			}
+			results, err = repo.Bulk(ctx, ops, false)
+			if err != nil {
+				t.Fatalf("Bulk non-atomic: %v", err)
+			}
+			if results[0].Err != nil {
+				t.Fatalf("results[0].Err = %v, want nil", results[0].Err)
+			}
+			if !errors.Is(results[1].Err, ErrEmailTaken) {
+				t.Fatalf("results[1].Err = %v, want ErrEmailTaken", results[1].Err)
+			}
+			if _, err := repo.GetByEmail(ctx, "new1@example.com"); err != nil {
+				t.Fatalf("non-atomic batch should keep the successful create, got err = %v", err)
+			}
+		})
+	}
+}